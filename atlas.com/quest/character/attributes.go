@@ -1,9 +1,24 @@
 package character
 
 type attributes struct {
-	JobId uint16 `json:"jobId"`
-	MapId uint32 `json:"mapId"`
-	Level byte   `json:"level"`
-	Fame  int16  `json:"fame"`
-	Meso  uint32 `json:"meso"`
+	WorldId byte   `json:"worldId"`
+	JobId   uint16 `json:"jobId"`
+	MapId   uint32 `json:"mapId"`
+	Level   byte   `json:"level"`
+	Fame    int16  `json:"fame"`
+	Meso    uint32 `json:"meso"`
+}
+
+type itemInputDataContainer struct {
+	Data itemDataBody `json:"data"`
+}
+
+type itemDataBody struct {
+	Type       string         `json:"type"`
+	Attributes itemAttributes `json:"attributes"`
+}
+
+type itemAttributes struct {
+	ItemId   uint32 `json:"itemId"`
+	Quantity uint32 `json:"quantity"`
 }
@@ -0,0 +1,9 @@
+package buff
+
+import "time"
+
+type attributes struct {
+	SourceId  int32     `json:"sourceId"`
+	Level     byte      `json:"level"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,22 @@
+package buff
+
+import "time"
+
+// Model is a buff active on a character. Buffs granted by items carry the negated item id as their source.
+type Model struct {
+	sourceId  int32
+	level     byte
+	expiresAt time.Time
+}
+
+func (m Model) SourceId() int32 {
+	return m.sourceId
+}
+
+func (m Model) Level() byte {
+	return m.level
+}
+
+func (m Model) ExpiresAt() time.Time {
+	return m.expiresAt
+}
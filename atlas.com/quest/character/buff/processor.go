@@ -0,0 +1,30 @@
+package buff
+
+import (
+	"atlas-quest/model"
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+)
+
+func ByCharacterModelProvider(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) model.SliceProvider[Model] {
+	return func(characterId uint32) model.SliceProvider[Model] {
+		return requests.SliceProvider[attributes, Model](l, span)(requestByCharacter(t, characterId), makeModel)
+	}
+}
+
+func GetByCharacter(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) ([]Model, error) {
+	return func(characterId uint32) ([]Model, error) {
+		return ByCharacterModelProvider(l, span, t)(characterId)()
+	}
+}
+
+func makeModel(body requests.DataBody[attributes]) (Model, error) {
+	att := body.Attributes
+	return Model{
+		sourceId:  att.SourceId,
+		level:     att.Level,
+		expiresAt: att.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package inventory
+
+type itemAttributes struct {
+	ItemId   uint32 `json:"itemId"`
+	Slot     int16  `json:"slot"`
+	Quantity uint32 `json:"quantity"`
+}
+
+type itemInputDataContainer struct {
+	Data itemInputDataBody `json:"data"`
+}
+
+type itemInputDataBody struct {
+	Type       string              `json:"type"`
+	Attributes itemInputAttributes `json:"attributes"`
+}
+
+type itemInputAttributes struct {
+	ItemId   uint32 `json:"itemId"`
+	Quantity uint32 `json:"quantity"`
+}
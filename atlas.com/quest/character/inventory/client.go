@@ -0,0 +1,62 @@
+package inventory
+
+import (
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"sync"
+)
+
+// Client is the source of character inventory contents. By default this is the character service, but it may be
+// replaced with SetClient.
+type Client interface {
+	Equipment(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, characterId uint32) ([]Item, error)
+	Inventory(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, characterId uint32, inventoryType string) ([]Item, error)
+	RemoveItem(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, characterId uint32, itemId uint32, quantity uint32) error
+}
+
+var client Client = restClient{}
+var lock sync.RWMutex
+
+func SetClient(c Client) {
+	lock.Lock()
+	defer lock.Unlock()
+	client = c
+}
+
+func GetClient() Client {
+	lock.RLock()
+	defer lock.RUnlock()
+	return client
+}
+
+type restClient struct {
+}
+
+func (r restClient) Equipment(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, characterId uint32) ([]Item, error) {
+	return requests.SliceProvider[itemAttributes, Item](l, span)(requestEquipment(t, characterId), makeItem)()
+}
+
+func (r restClient) Inventory(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, characterId uint32, inventoryType string) ([]Item, error) {
+	return requests.SliceProvider[itemAttributes, Item](l, span)(requestInventory(t, characterId, inventoryType), makeItem)()
+}
+
+func (r restClient) RemoveItem(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, characterId uint32, itemId uint32, quantity uint32) error {
+	return requestRemoveItem(t, characterId, itemId, quantity)(l, span)
+}
+
+func makeItem(body requests.DataBody[itemAttributes]) (Item, error) {
+	id, err := strconv.ParseUint(body.Id, 10, 32)
+	if err != nil {
+		return Item{}, err
+	}
+	att := body.Attributes
+	return Item{
+		id:       uint32(id),
+		itemId:   att.ItemId,
+		slot:     att.Slot,
+		quantity: att.Quantity,
+	}, nil
+}
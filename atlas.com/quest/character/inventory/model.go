@@ -0,0 +1,56 @@
+package inventory
+
+const (
+	TypeEquip = "equip"
+	TypeUse   = "use"
+	TypeSetup = "setup"
+	TypeEtc   = "etc"
+	TypeCash  = "cash"
+)
+
+// TypeOf names the inventory the item is held in.
+func TypeOf(itemId uint32) string {
+	switch itemId / 1000000 {
+	case 1:
+		return TypeEquip
+	case 2:
+		return TypeUse
+	case 3:
+		return TypeSetup
+	case 5:
+		return TypeCash
+	}
+	return TypeEtc
+}
+
+type Item struct {
+	id       uint32
+	itemId   uint32
+	slot     int16
+	quantity uint32
+}
+
+func NewItem(id uint32, itemId uint32, slot int16, quantity uint32) Item {
+	return Item{id: id, itemId: itemId, slot: slot, quantity: quantity}
+}
+
+func (i Item) Id() uint32 {
+	return i.id
+}
+
+func (i Item) ItemId() uint32 {
+	return i.itemId
+}
+
+func (i Item) Slot() int16 {
+	return i.slot
+}
+
+func (i Item) Quantity() uint32 {
+	return i.quantity
+}
+
+// Equipped reports whether the item is worn, rather than held in the equipment inventory.
+func (i Item) Equipped() bool {
+	return i.slot < 0
+}
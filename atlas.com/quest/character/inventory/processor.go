@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+)
+
+// GetEquipped retrieves the item ids the character is currently wearing.
+func GetEquipped(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) (map[uint32]bool, error) {
+	return func(characterId uint32) (map[uint32]bool, error) {
+		is, err := GetClient().Equipment(l, span, t, characterId)
+		if err != nil {
+			return nil, err
+		}
+		results := make(map[uint32]bool)
+		for _, i := range is {
+			if i.Equipped() {
+				results[i.ItemId()] = true
+			}
+		}
+		return results, nil
+	}
+}
+
+// IsWearingAll reports whether the character is wearing every one of the items.
+func IsWearingAll(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, itemIds []uint32) bool {
+	return func(characterId uint32, itemIds []uint32) bool {
+		equipped, err := GetEquipped(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve equipment for character %d.", characterId)
+			return false
+		}
+		for _, id := range itemIds {
+			if !equipped[id] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// IsWearingAny reports whether the character is wearing at least one of the items.
+func IsWearingAny(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, itemIds []uint32) bool {
+	return func(characterId uint32, itemIds []uint32) bool {
+		equipped, err := GetEquipped(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve equipment for character %d.", characterId)
+			return false
+		}
+		for _, id := range itemIds {
+			if equipped[id] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// GetQuantities retrieves the quantity of each of the items the character holds. Only the inventories holding the
+// items are consulted.
+func GetQuantities(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, itemIds []uint32) (map[uint32]uint32, error) {
+	return func(characterId uint32, itemIds []uint32) (map[uint32]uint32, error) {
+		wanted := make(map[uint32]bool)
+		types := make(map[string]bool)
+		for _, id := range itemIds {
+			wanted[id] = true
+			types[TypeOf(id)] = true
+		}
+
+		results := make(map[uint32]uint32)
+		for it := range types {
+			is, err := GetClient().Inventory(l, span, t, characterId, it)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range is {
+				if wanted[i.ItemId()] {
+					results[i.ItemId()] += i.Quantity()
+				}
+			}
+		}
+		return results, nil
+	}
+}
+
+// RemoveItems takes the quantity of each of the items away from the character.
+func RemoveItems(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, items map[uint32]uint32) error {
+	return func(characterId uint32, items map[uint32]uint32) error {
+		for itemId, quantity := range items {
+			err := GetClient().RemoveItem(l, span, t, characterId, itemId, quantity)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package inventory
+
+import (
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	charactersServicePrefix string = "/ms/cos/"
+	charactersService              = requests.BaseRequest + charactersServicePrefix
+	charactersResource             = charactersService + "characters/"
+	equipmentResource              = charactersResource + "%d/inventories/equip"
+	inventoryResource              = charactersResource + "%d/inventories/%s"
+	itemsResource                  = charactersResource + "%d/items"
+)
+
+func requestEquipment(t tenant.Model, characterId uint32) requests.Request[itemAttributes] {
+	return requests.MakeGetRequest[itemAttributes](fmt.Sprintf(equipmentResource, characterId), requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
+
+func requestInventory(t tenant.Model, characterId uint32, inventoryType string) requests.Request[itemAttributes] {
+	return requests.MakeGetRequest[itemAttributes](fmt.Sprintf(inventoryResource, characterId, inventoryType), requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
+
+func requestRemoveItem(t tenant.Model, characterId uint32, itemId uint32, quantity uint32) func(l logrus.FieldLogger, span opentracing.Span) error {
+	return func(l logrus.FieldLogger, span opentracing.Span) error {
+		i := itemInputDataContainer{Data: itemInputDataBody{Type: "items", Attributes: itemInputAttributes{ItemId: itemId, Quantity: quantity}}}
+		return requests.Delete(l, span)(fmt.Sprintf(itemsResource, characterId), i, requests.SetHeader(tenant.HeaderKey, t.Id()))
+	}
+}
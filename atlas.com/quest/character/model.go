@@ -1,18 +1,23 @@
 package character
 
 type Model struct {
-	id    uint32
-	jobId uint16
-	mapId uint32
-	level byte
-	fame  int16
-	meso  uint32
+	id      uint32
+	worldId byte
+	jobId   uint16
+	mapId   uint32
+	level   byte
+	fame    int16
+	meso    uint32
 }
 
 func (a Model) Id() uint32 {
 	return a.id
 }
 
+func (a Model) WorldId() byte {
+	return a.worldId
+}
+
 func (a Model) JobId() uint16 {
 	return a.jobId
 }
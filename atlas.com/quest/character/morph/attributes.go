@@ -0,0 +1,5 @@
+package morph
+
+type attributes struct {
+	MorphId uint32 `json:"morphId"`
+}
@@ -0,0 +1,15 @@
+package morph
+
+type Model struct {
+	characterId uint32
+	morphId     uint32
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+// MorphId is the form the character has taken, or zero when not morphed.
+func (m Model) MorphId() uint32 {
+	return m.morphId
+}
@@ -0,0 +1,18 @@
+package morph
+
+import (
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"fmt"
+)
+
+const (
+	charactersServicePrefix string = "/ms/cos/"
+	charactersService              = requests.BaseRequest + charactersServicePrefix
+	charactersResource             = charactersService + "characters/"
+	morphResource                  = charactersResource + "%d/morph"
+)
+
+func requestByCharacter(t tenant.Model, characterId uint32) requests.Request[attributes] {
+	return requests.MakeGetRequest[attributes](fmt.Sprintf(morphResource, characterId), requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
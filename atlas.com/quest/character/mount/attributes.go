@@ -0,0 +1,8 @@
+package mount
+
+type attributes struct {
+	ItemId    uint32 `json:"itemId"`
+	Level     byte   `json:"level"`
+	Exp       uint32 `json:"exp"`
+	Tiredness byte   `json:"tiredness"`
+}
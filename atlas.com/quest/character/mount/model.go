@@ -0,0 +1,30 @@
+package mount
+
+// Model is the taming mob a character rides.
+type Model struct {
+	characterId uint32
+	itemId      uint32
+	level       byte
+	exp         uint32
+	tiredness   byte
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+func (m Model) ItemId() uint32 {
+	return m.itemId
+}
+
+func (m Model) Level() byte {
+	return m.level
+}
+
+func (m Model) Exp() uint32 {
+	return m.exp
+}
+
+func (m Model) Tiredness() byte {
+	return m.tiredness
+}
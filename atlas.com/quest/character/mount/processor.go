@@ -0,0 +1,37 @@
+package mount
+
+import (
+	"atlas-quest/model"
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"strconv"
+)
+
+func ByCharacterModelProvider(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) model.Provider[Model] {
+	return func(characterId uint32) model.Provider[Model] {
+		return requests.Provider[attributes, Model](l, span)(requestByCharacter(t, characterId), makeModel)
+	}
+}
+
+func GetByCharacter(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) (Model, error) {
+	return func(characterId uint32) (Model, error) {
+		return ByCharacterModelProvider(l, span, t)(characterId)()
+	}
+}
+
+func makeModel(body requests.DataBody[attributes]) (Model, error) {
+	id, err := strconv.ParseUint(body.Id, 10, 32)
+	if err != nil {
+		return Model{}, err
+	}
+	att := body.Attributes
+	return Model{
+		characterId: uint32(id),
+		itemId:      att.ItemId,
+		level:       att.Level,
+		exp:         att.Exp,
+		tiredness:   att.Tiredness,
+	}, nil
+}
@@ -0,0 +1,18 @@
+package mount
+
+import (
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"fmt"
+)
+
+const (
+	charactersServicePrefix string = "/ms/cos/"
+	charactersService              = requests.BaseRequest + charactersServicePrefix
+	charactersResource             = charactersService + "characters/"
+	mountResource                  = charactersResource + "%d/mount"
+)
+
+func requestByCharacter(t tenant.Model, characterId uint32) requests.Request[attributes] {
+	return requests.MakeGetRequest[attributes](fmt.Sprintf(mountResource, characterId), requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
@@ -0,0 +1,27 @@
+package pet
+
+type attributes struct {
+	ItemId    uint32 `json:"itemId"`
+	Name      string `json:"name"`
+	Level     byte   `json:"level"`
+	Closeness uint16 `json:"closeness"`
+	Fullness  byte   `json:"fullness"`
+	Speed     byte   `json:"speed"`
+	Skills    uint16 `json:"skills"`
+	Slot      int8   `json:"slot"`
+}
+
+type adjustmentInputDataContainer struct {
+	Data adjustmentDataBody `json:"data"`
+}
+
+type adjustmentDataBody struct {
+	Type       string               `json:"type"`
+	Attributes adjustmentAttributes `json:"attributes"`
+}
+
+type adjustmentAttributes struct {
+	Closeness int16  `json:"closeness"`
+	Speed     int8   `json:"speed"`
+	Skills    uint16 `json:"skills"`
+}
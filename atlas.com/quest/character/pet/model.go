@@ -0,0 +1,64 @@
+package pet
+
+const (
+	SkillRecall       uint16 = 0x80
+	SkillAutoSpeaking uint16 = 0x100
+)
+
+type Model struct {
+	id        uint64
+	itemId    uint32
+	name      string
+	level     byte
+	closeness uint16
+	fullness  byte
+	speed     byte
+	skills    uint16
+	slot      int8
+}
+
+func (m Model) Id() uint64 {
+	return m.id
+}
+
+func (m Model) ItemId() uint32 {
+	return m.itemId
+}
+
+func (m Model) Name() string {
+	return m.name
+}
+
+func (m Model) Level() byte {
+	return m.level
+}
+
+func (m Model) Closeness() uint16 {
+	return m.closeness
+}
+
+func (m Model) Fullness() byte {
+	return m.fullness
+}
+
+func (m Model) Speed() byte {
+	return m.speed
+}
+
+// Skills is the set of skill flags the pet has been taught.
+func (m Model) Skills() uint16 {
+	return m.skills
+}
+
+func (m Model) HasSkill(skill uint16) bool {
+	return m.skills&skill == skill
+}
+
+// Slot is the position of the pet when summoned, or negative when not.
+func (m Model) Slot() int8 {
+	return m.slot
+}
+
+func (m Model) Summoned() bool {
+	return m.slot >= 0
+}
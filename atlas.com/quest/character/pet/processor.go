@@ -0,0 +1,103 @@
+package pet
+
+import (
+	"atlas-quest/model"
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strconv"
+)
+
+func ByCharacterModelProvider(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) model.SliceProvider[Model] {
+	return func(characterId uint32) model.SliceProvider[Model] {
+		return requests.SliceProvider[attributes, Model](l, span)(requestByCharacter(t, characterId), makeModel)
+	}
+}
+
+// GetSummoned retrieves the pets the character has summoned, ordered by slot, so the lead pet is first.
+func GetSummoned(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) ([]Model, error) {
+	return func(characterId uint32) ([]Model, error) {
+		ps, err := ByCharacterModelProvider(l, span, t)(characterId)()
+		if err != nil {
+			return nil, err
+		}
+		results := make([]Model, 0)
+		for _, p := range ps {
+			if p.Summoned() {
+				results = append(results, p)
+			}
+		}
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Slot() < results[j].Slot()
+		})
+		return results, nil
+	}
+}
+
+// GetLead retrieves the first pet the character has summoned.
+func GetLead(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) (Model, error) {
+	return func(characterId uint32) (Model, error) {
+		ps, err := GetSummoned(l, span, t)(characterId)
+		if err != nil {
+			return Model{}, err
+		}
+		if len(ps) == 0 {
+			return Model{}, errors.New(fmt.Sprintf("character %d has no pet summoned", characterId))
+		}
+		return ps[0], nil
+	}
+}
+
+func adjust(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, petId uint64, closeness int16, speed int8, skills uint16) error {
+	return func(characterId uint32, petId uint64, closeness int16, speed int8, skills uint16) error {
+		_, errResp, err := requestAdjustment(t, characterId, petId, closeness, speed, skills)(l, span)
+		if err != nil {
+			return err
+		}
+		if len(errResp.Errors) > 0 {
+			return errors.New(fmt.Sprintf("unable to adjust pet %d of character %d: %s", petId, characterId, errResp.Errors[0].Detail))
+		}
+		return nil
+	}
+}
+
+func GainCloseness(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, petId uint64, amount int16) error {
+	return func(characterId uint32, petId uint64, amount int16) error {
+		return adjust(l, span, t)(characterId, petId, amount, 0, 0)
+	}
+}
+
+func ChangeSpeed(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, petId uint64, amount int8) error {
+	return func(characterId uint32, petId uint64, amount int8) error {
+		return adjust(l, span, t)(characterId, petId, 0, amount, 0)
+	}
+}
+
+func AddSkill(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, petId uint64, skill uint16) error {
+	return func(characterId uint32, petId uint64, skill uint16) error {
+		return adjust(l, span, t)(characterId, petId, 0, 0, skill)
+	}
+}
+
+func makeModel(body requests.DataBody[attributes]) (Model, error) {
+	id, err := strconv.ParseUint(body.Id, 10, 64)
+	if err != nil {
+		return Model{}, err
+	}
+	att := body.Attributes
+	return Model{
+		id:        id,
+		itemId:    att.ItemId,
+		name:      att.Name,
+		level:     att.Level,
+		closeness: att.Closeness,
+		fullness:  att.Fullness,
+		speed:     att.Speed,
+		skills:    att.Skills,
+		slot:      att.Slot,
+	}, nil
+}
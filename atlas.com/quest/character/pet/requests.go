@@ -0,0 +1,24 @@
+package pet
+
+import (
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"fmt"
+)
+
+const (
+	charactersServicePrefix string = "/ms/cos/"
+	charactersService              = requests.BaseRequest + charactersServicePrefix
+	charactersResource             = charactersService + "characters/"
+	petsResource                   = charactersResource + "%d/pets"
+	petAdjustmentsResource         = petsResource + "/%d/adjustments"
+)
+
+func requestByCharacter(t tenant.Model, characterId uint32) requests.Request[attributes] {
+	return requests.MakeGetRequest[attributes](fmt.Sprintf(petsResource, characterId), requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
+
+func requestAdjustment(t tenant.Model, characterId uint32, petId uint64, closeness int16, speed int8, skills uint16) requests.PostRequest[attributes] {
+	i := adjustmentInputDataContainer{Data: adjustmentDataBody{Type: "adjustments", Attributes: adjustmentAttributes{Closeness: closeness, Speed: speed, Skills: skills}}}
+	return requests.MakePostRequest[attributes](fmt.Sprintf(petAdjustmentsResource, characterId, petId), i, requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
@@ -1,22 +1,31 @@
 package character
 
 import (
+	"atlas-quest/character/buff"
+	"atlas-quest/character/inventory"
+	"atlas-quest/character/morph"
+	"atlas-quest/character/mount"
+	"atlas-quest/character/pet"
+	"atlas-quest/character/skill"
 	"atlas-quest/model"
 	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"strconv"
 )
 
-func ByIdModelProvider(l logrus.FieldLogger, span opentracing.Span) func(id uint32) model.Provider[Model] {
+func ByIdModelProvider(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(id uint32) model.Provider[Model] {
 	return func(id uint32) model.Provider[Model] {
-		return requests.Provider[attributes, Model](l, span)(requestById(id), makeModel)
+		return requests.Provider[attributes, Model](l, span)(requestById(t, id), makeModel)
 	}
 }
 
-func GetById(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32) (Model, error) {
+func GetById(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) (Model, error) {
 	return func(characterId uint32) (Model, error) {
-		return ByIdModelProvider(l, span)(characterId)()
+		return ByIdModelProvider(l, span, t)(characterId)()
 	}
 }
 
@@ -27,21 +36,22 @@ func makeModel(ca requests.DataBody[attributes]) (Model, error) {
 	}
 	att := ca.Attributes
 	r := Model{
-		id:    uint32(cid),
-		jobId: att.JobId,
-		mapId: att.MapId,
-		level: att.Level,
-		fame:  att.Fame,
-		meso:  att.Meso,
+		id:      uint32(cid),
+		worldId: att.WorldId,
+		jobId:   att.JobId,
+		mapId:   att.MapId,
+		level:   att.Level,
+		fame:    att.Fame,
+		meso:    att.Meso,
 	}
 	return r, nil
 }
 
 type Criteria func(Model) bool
 
-func MeetsCriteria(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, criteria ...Criteria) bool {
+func MeetsCriteria(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, criteria ...Criteria) bool {
 	return func(characterId uint32, criteria ...Criteria) bool {
-		c, err := GetById(l, span)(characterId)
+		c, err := GetById(l, span, t)(characterId)
 		if err != nil {
 			l.WithError(err).Errorf("Unable to retrieve character %d for criteria check.", characterId)
 			return false
@@ -55,9 +65,9 @@ func MeetsCriteria(l logrus.FieldLogger, span opentracing.Span) func(characterId
 	}
 }
 
-func IsJob(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, options []uint16) bool {
+func IsJob(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, options []uint16) bool {
 	return func(characterId uint32, options []uint16) bool {
-		return MeetsCriteria(l, span)(characterId, IsJobCriteria(options))
+		return MeetsCriteria(l, span, t)(characterId, IsJobCriteria(options))
 	}
 }
 
@@ -72,9 +82,9 @@ func IsJobCriteria(options []uint16) Criteria {
 	}
 }
 
-func InMap(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, mapId uint32) bool {
+func InMap(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, mapId uint32) bool {
 	return func(characterId uint32, mapId uint32) bool {
-		return MeetsCriteria(l, span)(characterId, InMapCriteria(mapId))
+		return MeetsCriteria(l, span, t)(characterId, InMapCriteria(mapId))
 	}
 }
 
@@ -84,16 +94,30 @@ func InMapCriteria(mapId uint32) Criteria {
 	}
 }
 
-func HasItems(_ logrus.FieldLogger, _ opentracing.Span) func(characterId uint32, items map[uint32]uint32) bool {
+// HasItems reports whether the character holds at least the quantity of each of the items.
+func HasItems(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, items map[uint32]uint32) bool {
 	return func(characterId uint32, items map[uint32]uint32) bool {
-		//TODO
-		return false
+		ids := make([]uint32, 0, len(items))
+		for id := range items {
+			ids = append(ids, id)
+		}
+		held, err := inventory.GetQuantities(l, span, t)(characterId, ids)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve items for character %d.", characterId)
+			return false
+		}
+		for id, quantity := range items {
+			if held[id] < quantity {
+				return false
+			}
+		}
+		return true
 	}
 }
 
-func IsMinimalLevel(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, level byte) bool {
+func IsMinimalLevel(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, level byte) bool {
 	return func(characterId uint32, level byte) bool {
-		return MeetsCriteria(l, span)(characterId, MinimalLevelCriteria(level))
+		return MeetsCriteria(l, span, t)(characterId, MinimalLevelCriteria(level))
 	}
 }
 
@@ -103,9 +127,9 @@ func MinimalLevelCriteria(level byte) Criteria {
 	}
 }
 
-func IsLevel(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, level byte) bool {
+func IsLevel(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, level byte) bool {
 	return func(characterId uint32, level byte) bool {
-		return MeetsCriteria(l, span)(characterId, IsLevelCriteria(level))
+		return MeetsCriteria(l, span, t)(characterId, IsLevelCriteria(level))
 	}
 }
 
@@ -115,9 +139,9 @@ func IsLevelCriteria(level byte) Criteria {
 	}
 }
 
-func IsPopularityLevel(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, popularity int16) bool {
+func IsPopularityLevel(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, popularity int16) bool {
 	return func(characterId uint32, popularity int16) bool {
-		return MeetsCriteria(l, span)(characterId, MinimalPopularityCriteria(popularity))
+		return MeetsCriteria(l, span, t)(characterId, MinimalPopularityCriteria(popularity))
 	}
 }
 
@@ -127,9 +151,9 @@ func MinimalPopularityCriteria(popularity int16) Criteria {
 	}
 }
 
-func IsMaximalLevel(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, level byte) bool {
+func IsMaximalLevel(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, level byte) bool {
 	return func(characterId uint32, level byte) bool {
-		return MeetsCriteria(l, span)(characterId, MaximalLevelCriteria(level))
+		return MeetsCriteria(l, span, t)(characterId, MaximalLevelCriteria(level))
 	}
 }
 
@@ -139,48 +163,104 @@ func MaximalLevelCriteria(level byte) Criteria {
 	}
 }
 
-func IsMorphed(_ logrus.FieldLogger, _ opentracing.Span) func(characterId uint32, morph uint32) bool {
-	return func(characterId uint32, morph uint32) bool {
-		//TODO
-		return false
+func IsMinimalWorld(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, worldId byte) bool {
+	return func(characterId uint32, worldId byte) bool {
+		return MeetsCriteria(l, span, t)(characterId, MinimalWorldCriteria(worldId))
 	}
 }
 
-func HasSkill(_ logrus.FieldLogger, _ opentracing.Span) func(characterId uint32, skills map[uint32]uint32) bool {
-	return func(characterId uint32, skills map[uint32]uint32) bool {
-		//TODO
-		return false
+func MinimalWorldCriteria(worldId byte) Criteria {
+	return func(c Model) bool {
+		return c.WorldId() >= worldId
+	}
+}
+
+func IsMaximalWorld(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, worldId byte) bool {
+	return func(characterId uint32, worldId byte) bool {
+		return MeetsCriteria(l, span, t)(characterId, MaximalWorldCriteria(worldId))
+	}
+}
+
+func MaximalWorldCriteria(worldId byte) Criteria {
+	return func(c Model) bool {
+		return worldId >= c.WorldId()
+	}
+}
+
+func IsMorphed(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, morphId uint32) bool {
+	return func(characterId uint32, morphId uint32) bool {
+		m, err := morph.GetByCharacter(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve morph for character %d.", characterId)
+			return false
+		}
+		return m.MorphId() == morphId
+	}
+}
+
+// HasSkill checks the character's skills against those provided. Skills flagged for acquisition must have been
+// learned, while the remainder must not have been.
+func HasSkill(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, skills map[uint32]bool) bool {
+	return func(characterId uint32, skills map[uint32]bool) bool {
+		ss, err := skill.GetByCharacter(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve skills for character %d.", characterId)
+			return false
+		}
+		acquired := make(map[uint32]bool)
+		for _, s := range ss {
+			acquired[s.Id()] = s.Acquired()
+		}
+		for id, acquire := range skills {
+			if acquired[id] != acquire {
+				return false
+			}
+		}
+		return true
 	}
 }
 
-func HasBuff(_ logrus.FieldLogger, _ opentracing.Span) func(characterId uint32, buffId int) bool {
+func HasBuff(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, buffId int) bool {
 	return func(characterId uint32, buffId int) bool {
-		//TODO
-		return false
+		active, err := isBuffActive(l, span, t)(characterId, buffId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve buffs for character %d.", characterId)
+			return false
+		}
+		return active
 	}
 }
 
-func GetMonsterBook(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32) (map[uint32]uint32, error) {
-	return func(characterId uint32) (map[uint32]uint32, error) {
-		//TODO
-		return make(map[uint32]uint32), nil
+// LacksBuff is the inverse of HasBuff, except that it also fails when the character's buffs cannot be retrieved.
+func LacksBuff(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, buffId int) bool {
+	return func(characterId uint32, buffId int) bool {
+		active, err := isBuffActive(l, span, t)(characterId, buffId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve buffs for character %d.", characterId)
+			return false
+		}
+		return !active
 	}
 }
 
-func MonsterBookCount(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32) uint32 {
-	return func(characterId uint32) uint32 {
-		mb, err := GetMonsterBook(l, span)(characterId)
+func isBuffActive(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, buffId int) (bool, error) {
+	return func(characterId uint32, buffId int) (bool, error) {
+		bs, err := buff.GetByCharacter(l, span, t)(characterId)
 		if err != nil {
-			l.WithError(err).Errorf("Unable to retrieve monster book for character %d.", characterId)
-			return 0
+			return false, err
 		}
-		return uint32(len(mb))
+		for _, b := range bs {
+			if int(b.SourceId()) == buffId {
+				return true, nil
+			}
+		}
+		return false, nil
 	}
 }
 
-func HasMinimalMeso(l logrus.FieldLogger, span opentracing.Span) func(characterId uint32, meso uint32) bool {
+func HasMinimalMeso(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, meso uint32) bool {
 	return func(characterId uint32, meso uint32) bool {
-		return MeetsCriteria(l, span)(characterId, MinimalMesoCriteria(meso))
+		return MeetsCriteria(l, span, t)(characterId, MinimalMesoCriteria(meso))
 	}
 }
 
@@ -189,3 +269,81 @@ func MinimalMesoCriteria(meso uint32) Criteria {
 		return c.Meso() >= meso
 	}
 }
+
+// GainItem awards the character the quantity of the item.
+func GainItem(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, itemId uint32, quantity uint32) error {
+	return func(characterId uint32, itemId uint32, quantity uint32) error {
+		_, errResp, err := requestGainItem(t, characterId, itemId, quantity)(l, span)
+		if err != nil {
+			return err
+		}
+		if len(errResp.Errors) > 0 {
+			return errors.New(fmt.Sprintf("unable to award item %d to character %d: %s", itemId, characterId, errResp.Errors[0].Detail))
+		}
+		return nil
+	}
+}
+
+// HasPet reports whether the character has summoned a pet of one of the types provided.
+func HasPet(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, itemIds []uint32) bool {
+	return func(characterId uint32, itemIds []uint32) bool {
+		ps, err := pet.GetSummoned(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve pets for character %d.", characterId)
+			return false
+		}
+		for _, p := range ps {
+			for _, id := range itemIds {
+				if p.ItemId() == id {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// HasPetCloseness reports whether any of the character's summoned pets are at least as close as provided.
+func HasPetCloseness(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, closeness uint16) bool {
+	return func(characterId uint32, closeness uint16) bool {
+		ps, err := pet.GetSummoned(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve pets for character %d.", characterId)
+			return false
+		}
+		for _, p := range ps {
+			if p.Closeness() >= closeness {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasPetWithoutSkill reports whether any of the character's summoned pets has yet to learn the skill.
+func HasPetWithoutSkill(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, skill uint16) bool {
+	return func(characterId uint32, skill uint16) bool {
+		ps, err := pet.GetSummoned(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve pets for character %d.", characterId)
+			return false
+		}
+		for _, p := range ps {
+			if !p.HasSkill(skill) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func IsMinimalMountLevel(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32, level byte) bool {
+	return func(characterId uint32, level byte) bool {
+		m, err := mount.GetByCharacter(l, span, t)(characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve mount for character %d.", characterId)
+			return false
+		}
+		return m.Level() >= level
+	}
+}
@@ -0,0 +1,164 @@
+package quest
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ErrStatusChanged is returned when the character's standing in the quest is no longer that the change was made from,
+// typically as a concurrent request changed it first.
+var ErrStatusChanged = errors.New("quest status changed")
+
+// transition applies the changes to the character's record of the quest, provided its status is still from. The status
+// is checked by the update itself, so of several concurrent transitions from the same status only one takes effect.
+func transition(tx *gorm.DB, characterId uint32, questId uint16, from string, changes map[string]interface{}) error {
+	res := tx.Model(&entity{}).Where("character_id = ? AND quest_id = ? AND status = ?", characterId, questId, from).Updates(changes)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return ErrStatusChanged
+	}
+	return nil
+}
+
+// start records the character as having started the quest, replacing any previous progress. The quest must still have
+// the status from, where a quest the character has no record of is not started.
+func start(db *gorm.DB, characterId uint32, questId uint16, from string) (Model, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := transition(tx, characterId, questId, from, map[string]interface{}{"status": StatusStarted, "started_at": now, "completed_at": nil, "info": ""})
+		if errors.Is(err, ErrStatusChanged) && from == StatusNotStarted {
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity{CharacterId: characterId, QuestId: questId, Status: StatusStarted, StartedAt: now})
+			err = res.Error
+			if err == nil && res.RowsAffected != 1 {
+				err = ErrStatusChanged
+			}
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.Where(&progressEntity{CharacterId: characterId, QuestId: questId}).Delete(&progressEntity{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where(&entity{CharacterId: characterId, QuestId: questId}).First(&result).Error
+	})
+	if err != nil {
+		return Model{}, err
+	}
+	return makeModel(result)
+}
+
+// complete records the quest the character has in progress as completed.
+func complete(db *gorm.DB, characterId uint32, questId uint16) (Model, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := transition(tx, characterId, questId, StatusStarted, map[string]interface{}{"status": StatusCompleted, "completed_at": time.Now()})
+		if err != nil {
+			return err
+		}
+		return tx.Where(&entity{CharacterId: characterId, QuestId: questId}).First(&result).Error
+	})
+	if err != nil {
+		return Model{}, err
+	}
+	return makeModel(result)
+}
+
+// forfeit abandons the quest in progress, discarding its kills and info and counting the forfeit.
+func forfeit(db *gorm.DB, characterId uint32, questId uint16) (Model, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := transition(tx, characterId, questId, StatusStarted, map[string]interface{}{"status": StatusNotStarted, "info": "", "forfeit_count": gorm.Expr("forfeit_count + 1"), "forfeited_at": time.Now()})
+		if err != nil {
+			return err
+		}
+		err = tx.Where(&progressEntity{CharacterId: characterId, QuestId: questId}).Delete(&progressEntity{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where(&entity{CharacterId: characterId, QuestId: questId}).First(&result).Error
+	})
+	if err != nil {
+		return Model{}, err
+	}
+	return makeModel(result)
+}
+
+// addKills credits the character with killing the monster towards the quest, up to limit. The count is raised in a
+// single statement, so concurrent kills are not lost.
+func addKills(db *gorm.DB, characterId uint32, questId uint16, mobId uint32, amount uint32, limit uint32) (Progress, error) {
+	var result progressEntity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		initial := amount
+		if initial > limit {
+			initial = limit
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "character_id"}, {Name: "quest_id"}, {Name: "mob_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("CASE WHEN count + ? > ? THEN ? ELSE count + ? END", amount, limit, limit, amount)}),
+		}).Create(&progressEntity{CharacterId: characterId, QuestId: questId, MobId: mobId, Count: initial}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where(&progressEntity{CharacterId: characterId, QuestId: questId, MobId: mobId}).First(&result).Error
+	})
+	if err != nil {
+		return Progress{}, err
+	}
+	return makeProgress(result)
+}
+
+// deleteAll removes every quest record and kill count the character holds.
+func deleteAll(db *gorm.DB, characterId uint32) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(&entity{CharacterId: characterId}).Delete(&entity{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where(&progressEntity{CharacterId: characterId}).Delete(&progressEntity{}).Error
+	})
+}
+
+// restore replaces the character's quest records and kill counts with those given.
+func restore(db *gorm.DB, characterId uint32, quests []Model, progress []Progress) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := deleteAll(tx, characterId)
+		if err != nil {
+			return err
+		}
+		for _, m := range quests {
+			e := entity{
+				CharacterId:  characterId,
+				QuestId:      m.Id(),
+				Status:       m.Status(),
+				StartedAt:    m.Started(),
+				Info:         m.Info(),
+				ForfeitCount: m.Forfeits(),
+			}
+			if !m.Completion().IsZero() {
+				completedAt := m.Completion()
+				e.CompletedAt = &completedAt
+			}
+			if !m.Forfeited().IsZero() {
+				forfeitedAt := m.Forfeited()
+				e.ForfeitedAt = &forfeitedAt
+			}
+			err = tx.Create(&e).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, p := range progress {
+			err = tx.Create(&progressEntity{CharacterId: characterId, QuestId: p.QuestId(), MobId: p.MobId(), Count: p.Count()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
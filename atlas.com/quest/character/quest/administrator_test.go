@@ -0,0 +1,54 @@
+package quest
+
+import (
+	"errors"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+)
+
+func TestTransitionsRequireExpectedStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Migration(db); err != nil {
+		t.Fatal(err)
+	}
+
+	const characterId = uint32(1)
+	const questId = uint16(1000)
+	steps := []struct {
+		name string
+		run  func() (Model, error)
+		want error
+	}{
+		{"complete before starting", func() (Model, error) { return complete(db, characterId, questId) }, ErrStatusChanged},
+		{"forfeit before starting", func() (Model, error) { return forfeit(db, characterId, questId) }, ErrStatusChanged},
+		{"start", func() (Model, error) { return start(db, characterId, questId, StatusNotStarted) }, nil},
+		{"start again", func() (Model, error) { return start(db, characterId, questId, StatusNotStarted) }, ErrStatusChanged},
+		{"forfeit", func() (Model, error) { return forfeit(db, characterId, questId) }, nil},
+		{"forfeit again", func() (Model, error) { return forfeit(db, characterId, questId) }, ErrStatusChanged},
+		{"restart", func() (Model, error) { return start(db, characterId, questId, StatusNotStarted) }, nil},
+		{"complete", func() (Model, error) { return complete(db, characterId, questId) }, nil},
+		{"complete again", func() (Model, error) { return complete(db, characterId, questId) }, ErrStatusChanged},
+		{"start from stale status", func() (Model, error) { return start(db, characterId, questId, StatusNotStarted) }, ErrStatusChanged},
+		{"repeat", func() (Model, error) { return start(db, characterId, questId, StatusCompleted) }, nil},
+	}
+	for _, s := range steps {
+		_, err = s.run()
+		if !errors.Is(err, s.want) {
+			t.Fatalf("%s = %v, want %v", s.name, err, s.want)
+		}
+	}
+
+	var e entity
+	err = db.Where(&entity{CharacterId: characterId, QuestId: questId}).First(&e).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Status != StatusStarted || e.ForfeitCount != 1 || e.CompletedAt != nil {
+		t.Fatalf("record = %s with %d forfeits, completed at %v, want %s with 1 forfeit, not completed", e.Status, e.ForfeitCount, e.CompletedAt, StatusStarted)
+	}
+}
@@ -0,0 +1,38 @@
+package quest
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{}, &progressEntity{})
+}
+
+type entity struct {
+	ID           uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId  uint32 `gorm:"not null;uniqueIndex:idx_character_quest"`
+	QuestId      uint16 `gorm:"not null;uniqueIndex:idx_character_quest"`
+	Status       string `gorm:"not null"`
+	StartedAt    time.Time
+	CompletedAt  *time.Time
+	Info         string `gorm:"not null;default:''"`
+	ForfeitCount uint32 `gorm:"not null;default:0"`
+	ForfeitedAt  *time.Time
+}
+
+func (e entity) TableName() string {
+	return "character_quests"
+}
+
+type progressEntity struct {
+	ID          uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId uint32 `gorm:"not null;uniqueIndex:idx_character_quest_mob"`
+	QuestId     uint16 `gorm:"not null;uniqueIndex:idx_character_quest_mob"`
+	MobId       uint32 `gorm:"not null;uniqueIndex:idx_character_quest_mob"`
+	Count       uint32 `gorm:"not null;default:0"`
+}
+
+func (e progressEntity) TableName() string {
+	return "character_quest_progress"
+}
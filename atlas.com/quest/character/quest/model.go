@@ -12,7 +12,17 @@ const (
 type Model struct {
 	id         uint16
 	status     string
+	started    time.Time
 	completion time.Time
+	info       string
+	forfeits   uint32
+	forfeited  time.Time
+}
+
+// NewModel describes the character's standing in a quest, as when restoring it from elsewhere. Zero times are left
+// unset.
+func NewModel(id uint16, status string, started time.Time, completion time.Time, info string, forfeits uint32, forfeited time.Time) Model {
+	return Model{id: id, status: status, started: started, completion: completion, info: info, forfeits: forfeits, forfeited: forfeited}
 }
 
 func (m Model) Id() uint16 {
@@ -23,6 +33,48 @@ func (m Model) Status() string {
 	return m.status
 }
 
+func (m Model) Started() time.Time {
+	return m.started
+}
+
 func (m Model) Completion() time.Time {
 	return m.completion
 }
+
+// Info is the free-form info record the quest holds for the character.
+func (m Model) Info() string {
+	return m.info
+}
+
+// Forfeits is the number of times the character has forfeited the quest.
+func (m Model) Forfeits() uint32 {
+	return m.forfeits
+}
+
+// Forfeited is when the character last forfeited the quest. It is the zero time should the quest never have been.
+func (m Model) Forfeited() time.Time {
+	return m.forfeited
+}
+
+// Progress is the number of a monster the character has killed towards a quest.
+type Progress struct {
+	questId uint16
+	mobId   uint32
+	count   uint32
+}
+
+func NewProgress(questId uint16, mobId uint32, count uint32) Progress {
+	return Progress{questId: questId, mobId: mobId, count: count}
+}
+
+func (p Progress) QuestId() uint16 {
+	return p.questId
+}
+
+func (p Progress) MobId() uint32 {
+	return p.mobId
+}
+
+func (p Progress) Count() uint32 {
+	return p.count
+}
@@ -1,36 +1,140 @@
 package quest
 
 import (
+	"atlas-quest/database"
 	"errors"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func ForCharacter(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32) ([]Model, error) {
+func ForCharacter(_ logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32) ([]Model, error) {
 	return func(characterId uint32) ([]Model, error) {
-		//TODO
-		return nil, nil
+		return database.ModelSliceProvider[Model, entity](db)(byCharacterEntityProvider(characterId), makeModel)()
 	}
 }
 
-func GetById(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, questId uint16) (Model, error) {
+// GetById retrieves the character's standing in the quest. A quest the character has no record of is not started.
+func GetById(_ logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32, questId uint16) (Model, error) {
 	return func(characterId uint32, questId uint16) (Model, error) {
-		//TODO
-		return Model{}, errors.New("not found")
+		m, err := database.ModelProvider[Model, entity](db)(byCharacterAndQuestEntityProvider(characterId, questId), makeModel)()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Model{id: questId, status: StatusNotStarted}, nil
+		}
+		return m, err
 	}
 }
 
+// HasMetMonsterRequirement reports whether the character has killed at least the number of each monster required.
 func HasMetMonsterRequirement(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, questId uint16, counts map[uint32]uint32) bool {
 	return func(characterId uint32, questId uint16, counts map[uint32]uint32) bool {
-		//TODD
-		return false
+		kills, err := GetKills(l, span, db)(characterId, questId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to retrieve quest %d progress for character %d. Assuming criteria is not met.", questId, characterId)
+			return false
+		}
+		for mobId, count := range counts {
+			if kills[mobId] < count {
+				return false
+			}
+		}
+		return true
 	}
 }
 
-func QuestsByStatus(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, status string) ([]Model, error) {
+// GetProgress retrieves the character's kills towards all quests.
+func GetProgress(_ logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32) ([]Progress, error) {
+	return func(characterId uint32) ([]Progress, error) {
+		return database.ModelSliceProvider[Progress, progressEntity](db)(progressByCharacterEntityProvider(characterId), makeProgress)()
+	}
+}
+
+// GetKills retrieves the character's kills towards the quest, keyed by monster id.
+func GetKills(_ logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32, questId uint16) (map[uint32]uint32, error) {
+	return func(characterId uint32, questId uint16) (map[uint32]uint32, error) {
+		ps, err := database.ModelSliceProvider[Progress, progressEntity](db)(progressByCharacterAndQuestEntityProvider(characterId, questId), makeProgress)()
+		if err != nil {
+			return nil, err
+		}
+		results := make(map[uint32]uint32)
+		for _, p := range ps {
+			results[p.MobId()] = p.Count()
+		}
+		return results, nil
+	}
+}
+
+// RecordKills credits the character with killing the monster towards the quest. The count held never exceeds limit.
+func RecordKills(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16, mobId uint32, amount uint32, limit uint32) (Progress, error) {
+	return func(characterId uint32, questId uint16, mobId uint32, amount uint32, limit uint32) (Progress, error) {
+		p, err := addKills(db, characterId, questId, mobId, amount, limit)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to record kills of monster %d towards quest %d for character %d.", mobId, questId, characterId)
+		}
+		return p, err
+	}
+}
+
+func QuestsByStatus(_ logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32, status string) ([]Model, error) {
 	return func(characterId uint32, status string) ([]Model, error) {
-		//TODO
-		return nil, nil
+		return database.ModelSliceProvider[Model, entity](db)(byCharacterAndStatusEntityProvider(characterId, status), makeModel)()
+	}
+}
+
+// Start records the character as having started the quest, provided its status is still from. ErrStatusChanged is
+// returned otherwise.
+func Start(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16, from string) (Model, error) {
+	return func(characterId uint32, questId uint16, from string) (Model, error) {
+		m, err := start(db, characterId, questId, from)
+		if err != nil && !errors.Is(err, ErrStatusChanged) {
+			l.WithError(err).Errorf("Unable to record quest %d as started for character %d.", questId, characterId)
+		}
+		return m, err
+	}
+}
+
+// Complete records the character as having completed the quest, which must be in progress. ErrStatusChanged is
+// returned otherwise.
+func Complete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16) (Model, error) {
+	return func(characterId uint32, questId uint16) (Model, error) {
+		m, err := complete(db, characterId, questId)
+		if err != nil && !errors.Is(err, ErrStatusChanged) {
+			l.WithError(err).Errorf("Unable to record quest %d as completed for character %d.", questId, characterId)
+		}
+		return m, err
+	}
+}
+
+// Forfeit records the character as having abandoned the quest, which must be in progress. ErrStatusChanged is returned
+// otherwise.
+func Forfeit(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16) (Model, error) {
+	return func(characterId uint32, questId uint16) (Model, error) {
+		m, err := forfeit(db, characterId, questId)
+		if err != nil && !errors.Is(err, ErrStatusChanged) {
+			l.WithError(err).Errorf("Unable to record quest %d as forfeited for character %d.", questId, characterId)
+		}
+		return m, err
+	}
+}
+
+// Delete removes all quest records and kill counts held for the character.
+func Delete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) error {
+	return func(characterId uint32) error {
+		err := deleteAll(db, characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to delete quest records of character %d.", characterId)
+		}
+		return err
+	}
+}
+
+// Restore replaces all quest records and kill counts held for the character with those given.
+func Restore(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, quests []Model, progress []Progress) error {
+	return func(characterId uint32, quests []Model, progress []Progress) error {
+		err := restore(db, characterId, quests, progress)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to restore quest records of character %d.", characterId)
+		}
+		return err
 	}
 }
@@ -0,0 +1,62 @@
+package quest
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/model"
+	"gorm.io/gorm"
+)
+
+func byCharacterEntityProvider(characterId uint32) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId})
+	}
+}
+
+func byCharacterAndStatusEntityProvider(characterId uint32, status string) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId, Status: status})
+	}
+}
+
+func byCharacterAndQuestEntityProvider(characterId uint32, questId uint16) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{CharacterId: characterId, QuestId: questId})
+	}
+}
+
+func makeModel(e entity) (Model, error) {
+	m := Model{
+		id:       e.QuestId,
+		status:   e.Status,
+		started:  e.StartedAt,
+		info:     e.Info,
+		forfeits: e.ForfeitCount,
+	}
+	if e.CompletedAt != nil {
+		m.completion = *e.CompletedAt
+	}
+	if e.ForfeitedAt != nil {
+		m.forfeited = *e.ForfeitedAt
+	}
+	return m, nil
+}
+
+func progressByCharacterEntityProvider(characterId uint32) database.EntitySliceProvider[progressEntity] {
+	return func(db *gorm.DB) model.SliceProvider[progressEntity] {
+		return database.SliceQuery[progressEntity](db, &progressEntity{CharacterId: characterId})
+	}
+}
+
+func progressByCharacterAndQuestEntityProvider(characterId uint32, questId uint16) database.EntitySliceProvider[progressEntity] {
+	return func(db *gorm.DB) model.SliceProvider[progressEntity] {
+		return database.SliceQuery[progressEntity](db, &progressEntity{CharacterId: characterId, QuestId: questId})
+	}
+}
+
+func makeProgress(e progressEntity) (Progress, error) {
+	return Progress{
+		questId: e.QuestId,
+		mobId:   e.MobId,
+		count:   e.Count,
+	}, nil
+}
@@ -2,6 +2,7 @@ package character
 
 import (
 	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
 	"fmt"
 )
 
@@ -12,6 +13,15 @@ const (
 	charactersById                 = charactersResource + "%d"
 )
 
-func requestById(characterId uint32) requests.Request[attributes] {
-	return requests.MakeGetRequest[attributes](fmt.Sprintf(charactersById, characterId))
+func requestById(t tenant.Model, characterId uint32) requests.Request[attributes] {
+	return requests.MakeGetRequest[attributes](fmt.Sprintf(charactersById, characterId), requests.SetHeader(tenant.HeaderKey, t.Id()))
+}
+
+const (
+	charactersItems = charactersResource + "%d/items"
+)
+
+func requestGainItem(t tenant.Model, characterId uint32, itemId uint32, quantity uint32) requests.PostRequest[itemAttributes] {
+	i := itemInputDataContainer{Data: itemDataBody{Type: "items", Attributes: itemAttributes{ItemId: itemId, Quantity: quantity}}}
+	return requests.MakePostRequest[itemAttributes](fmt.Sprintf(charactersItems, characterId), i, requests.SetHeader(tenant.HeaderKey, t.Id()))
 }
@@ -0,0 +1,6 @@
+package skill
+
+type attributes struct {
+	Level       byte `json:"level"`
+	MasterLevel byte `json:"masterLevel"`
+}
@@ -0,0 +1,24 @@
+package skill
+
+type Model struct {
+	id          uint32
+	level       byte
+	masterLevel byte
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) Level() byte {
+	return m.level
+}
+
+func (m Model) MasterLevel() byte {
+	return m.masterLevel
+}
+
+// Acquired reports whether the character has learned or been granted mastery of the skill.
+func (m Model) Acquired() bool {
+	return m.level > 0 || m.masterLevel > 0
+}
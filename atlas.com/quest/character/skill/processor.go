@@ -0,0 +1,35 @@
+package skill
+
+import (
+	"atlas-quest/model"
+	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"strconv"
+)
+
+func ByCharacterModelProvider(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) model.SliceProvider[Model] {
+	return func(characterId uint32) model.SliceProvider[Model] {
+		return requests.SliceProvider[attributes, Model](l, span)(requestByCharacter(t, characterId), makeModel)
+	}
+}
+
+func GetByCharacter(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(characterId uint32) ([]Model, error) {
+	return func(characterId uint32) ([]Model, error) {
+		return ByCharacterModelProvider(l, span, t)(characterId)()
+	}
+}
+
+func makeModel(body requests.DataBody[attributes]) (Model, error) {
+	id, err := strconv.ParseUint(body.Id, 10, 32)
+	if err != nil {
+		return Model{}, err
+	}
+	att := body.Attributes
+	return Model{
+		id:          uint32(id),
+		level:       att.Level,
+		masterLevel: att.MasterLevel,
+	}, nil
+}
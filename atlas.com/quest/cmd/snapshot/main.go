@@ -0,0 +1,35 @@
+package main
+
+import (
+	"atlas-quest/wz"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Compiles the WZ XML files in a directory into a snapshot which the service can load in place of parsing XML. The
+// snapshot holds the parsed node trees; quest models are still built from them when the service loads.
+func main() {
+	in := flag.String("wz", os.Getenv("WZ_DIR"), "directory containing the WZ XML files")
+	out := flag.String("out", "quest.snapshot", "path to write the snapshot to")
+	flag.Parse()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to create snapshot file %s: %s\n", *out, err)
+		os.Exit(1)
+	}
+
+	hash, err := wz.Compile(*in, f)
+	if err == nil {
+		err = f.Close()
+	} else {
+		_ = f.Close()
+	}
+	if err != nil {
+		_ = os.Remove(*out)
+		fmt.Fprintf(os.Stderr, "Unable to compile snapshot from %s: %s\n", *in, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Compiled %s into %s with content hash %s.\n", *in, *out, hash)
+}
@@ -58,8 +58,9 @@ func (d *DSNBuilder) Build() string {
 }
 
 type Configuration struct {
-	dsn        string
-	migrations []Migrator
+	dsn          string
+	databaseName string
+	migrations   []Migrator
 }
 
 type Configurator func(c *Configuration)
@@ -70,6 +71,13 @@ func SetMigrations(migrations ...Migrator) Configurator {
 	}
 }
 
+// SetDatabaseName connects to the named database, rather than that given by DB_NAME.
+func SetDatabaseName(value string) Configurator {
+	return func(c *Configuration) {
+		c.databaseName = value
+	}
+}
+
 type Migrator func(db *gorm.DB) error
 
 func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
@@ -103,12 +111,15 @@ func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
 	}
 
 	c := &Configuration{
-		dsn:        dsnBuilder.Build(),
 		migrations: make([]Migrator, 0),
 	}
 	for _, configurator := range configurators {
 		configurator(c)
 	}
+	if c.databaseName != "" {
+		dsnBuilder = dsnBuilder.SetDatabaseName(c.databaseName)
+	}
+	c.dsn = dsnBuilder.Build()
 
 	var db *gorm.DB
 	tryToConnect := func(attempt int) (bool, error) {
@@ -0,0 +1,67 @@
+package lifecycle
+
+import "time"
+
+type stateDataContainer struct {
+	Data stateDataBody `json:"data"`
+}
+
+type stateDataBody struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	Attributes stateAttributes `json:"attributes"`
+}
+
+type stateAttributes struct {
+	Version       uint32                   `json:"version"`
+	CharacterId   uint32                   `json:"characterId"`
+	ExportedAt    time.Time                `json:"exportedAt"`
+	Quests        []questAttributes        `json:"quests"`
+	Medals        []medalAttributes        `json:"medals"`
+	Conversations []conversationAttributes `json:"conversations"`
+	Cards         []cardAttributes         `json:"cards"`
+	PartyQuests   []partyQuestAttributes   `json:"partyQuests"`
+}
+
+type questAttributes struct {
+	QuestId     uint16               `json:"questId"`
+	Status      string               `json:"status"`
+	StartedAt   *time.Time           `json:"startedAt,omitempty"`
+	CompletedAt *time.Time           `json:"completedAt,omitempty"`
+	Info        string               `json:"info"`
+	Forfeits    uint32               `json:"forfeits"`
+	ForfeitedAt *time.Time           `json:"forfeitedAt,omitempty"`
+	Progress    []progressAttributes `json:"progress"`
+}
+
+type progressAttributes struct {
+	MobId uint32 `json:"mobId"`
+	Count uint32 `json:"count"`
+}
+
+type medalAttributes struct {
+	QuestId     uint16     `json:"questId"`
+	MedalId     uint32     `json:"medalId"`
+	Category    uint32     `json:"category"`
+	Status      string     `json:"status"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+type conversationAttributes struct {
+	QuestId uint16 `json:"questId"`
+	Phase   string `json:"phase"`
+	Passed  bool   `json:"passed"`
+}
+
+type cardAttributes struct {
+	CardId uint32 `json:"cardId"`
+	Level  uint32 `json:"level"`
+}
+
+type partyQuestAttributes struct {
+	PartyQuestId uint32 `json:"partyQuestId"`
+	Tries        uint32 `json:"tries"`
+	Clears       uint32 `json:"clears"`
+	BestTime     uint32 `json:"bestTime"`
+}
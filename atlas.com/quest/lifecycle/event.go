@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const StatusEventTypeDeleted = "DELETED"
+
+// statusEvent is the character status event published by the character service, delivered to the service as is.
+type statusEvent struct {
+	CharacterId uint32 `json:"characterId"`
+	Name        string `json:"name"`
+	WorldId     byte   `json:"worldId"`
+	Type        string `json:"type"`
+}
+
+// handleStatusEvent cascades the deletion of a character to the quest state held for it. Other status events are of no
+// interest to the service.
+func handleStatusEvent(l logrus.FieldLogger, db *gorm.DB) func(e statusEvent) error {
+	return func(e statusEvent) error {
+		if e.Type != StatusEventTypeDeleted {
+			l.Debugf("Ignoring character %d status event of type %s.", e.CharacterId, e.Type)
+			return nil
+		}
+		l.Debugf("Character %d of world %d was deleted.", e.CharacterId, e.WorldId)
+		return Delete(l, db)(e.CharacterId)
+	}
+}
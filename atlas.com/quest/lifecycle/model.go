@@ -0,0 +1,57 @@
+package lifecycle
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/medal"
+	"atlas-quest/monsterbook"
+	"atlas-quest/partyquest"
+	"atlas-quest/quest/conversation"
+)
+
+// Version is the revision of the quest state document produced by export. Documents of any other version are refused
+// on import.
+const Version = 1
+
+// State is the full quest state held for a character: its standing in each quest along with the info record, kill
+// counts, medal quests, conversation outcomes, monster book cards and party quest stats.
+type State struct {
+	characterId   uint32
+	quests        []characterquest.Model
+	progress      []characterquest.Progress
+	medals        []medal.Model
+	conversations []conversation.Outcome
+	cards         []monsterbook.Card
+	partyQuests   []partyquest.Stats
+}
+
+func NewState(characterId uint32, quests []characterquest.Model, progress []characterquest.Progress, medals []medal.Model, conversations []conversation.Outcome, cards []monsterbook.Card, partyQuests []partyquest.Stats) State {
+	return State{characterId: characterId, quests: quests, progress: progress, medals: medals, conversations: conversations, cards: cards, partyQuests: partyQuests}
+}
+
+func (s State) CharacterId() uint32 {
+	return s.characterId
+}
+
+func (s State) Quests() []characterquest.Model {
+	return s.quests
+}
+
+func (s State) Progress() []characterquest.Progress {
+	return s.progress
+}
+
+func (s State) Medals() []medal.Model {
+	return s.medals
+}
+
+func (s State) Conversations() []conversation.Outcome {
+	return s.conversations
+}
+
+func (s State) Cards() []monsterbook.Card {
+	return s.cards
+}
+
+func (s State) PartyQuests() []partyquest.Stats {
+	return s.partyQuests
+}
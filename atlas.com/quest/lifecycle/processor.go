@@ -0,0 +1,277 @@
+package lifecycle
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/medal"
+	"atlas-quest/monsterbook"
+	"atlas-quest/partyquest"
+	"atlas-quest/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+)
+
+var (
+	ErrInvalidState = errors.New("quest state does not match the loaded quest data")
+)
+
+// Delete removes everything held for the character: quest records and their info, kill counts, medal quests,
+// conversation outcomes, monster book cards and party quest stats. Either all of it is removed, or none.
+func Delete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) error {
+	return func(characterId uint32) error {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, d := range []func(characterId uint32) error{
+				characterquest.Delete(l, tx),
+				medal.Delete(l, tx),
+				conversation.Delete(l, tx),
+				monsterbook.Delete(l, tx),
+				partyquest.Delete(l, tx),
+			} {
+				err := d(characterId)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		l.Infof("Deleted quest state of character %d.", characterId)
+		return nil
+	}
+}
+
+// Export gathers the full quest state of the character, ordered by quest id. Cards are ordered by card id, and party
+// quest stats by party quest id.
+func Export(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32) (State, error) {
+	return func(characterId uint32) (State, error) {
+		qs, err := characterquest.ForCharacter(l, span, db)(characterId)
+		if err != nil {
+			return State{}, err
+		}
+		sort.Slice(qs, func(i, j int) bool {
+			return qs[i].Id() < qs[j].Id()
+		})
+
+		ps, err := characterquest.GetProgress(l, span, db)(characterId)
+		if err != nil {
+			return State{}, err
+		}
+		sort.Slice(ps, func(i, j int) bool {
+			if ps[i].QuestId() != ps[j].QuestId() {
+				return ps[i].QuestId() < ps[j].QuestId()
+			}
+			return ps[i].MobId() < ps[j].MobId()
+		})
+
+		ms, err := medal.GetAll(l, db)(characterId)
+		if err != nil {
+			return State{}, err
+		}
+		sort.Slice(ms, func(i, j int) bool {
+			return ms[i].QuestId() < ms[j].QuestId()
+		})
+
+		cs, err := conversation.GetOutcomes(l, db)(characterId)
+		if err != nil {
+			return State{}, err
+		}
+		sort.Slice(cs, func(i, j int) bool {
+			if cs[i].QuestId() != cs[j].QuestId() {
+				return cs[i].QuestId() < cs[j].QuestId()
+			}
+			return cs[i].Phase() > cs[j].Phase()
+		})
+
+		bs, err := monsterbook.GetCards(l, db)(characterId)
+		if err != nil {
+			return State{}, err
+		}
+		sort.Slice(bs, func(i, j int) bool {
+			return bs[i].CardId() < bs[j].CardId()
+		})
+
+		pqs, err := partyquest.GetStats(l, db)(characterId)
+		if err != nil {
+			return State{}, err
+		}
+		sort.Slice(pqs, func(i, j int) bool {
+			return pqs[i].PartyQuestId() < pqs[j].PartyQuestId()
+		})
+		return NewState(characterId, qs, ps, ms, cs, bs, pqs), nil
+	}
+}
+
+// Import replaces the quest state of the character with that given, once validated against the quest data loaded
+// for the tenant. Either all of the state is replaced, or none.
+func Import(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(characterId uint32, s State) error {
+	return func(characterId uint32, s State) error {
+		if problems := validate(t, s); len(problems) > 0 {
+			return errors.Join(append([]error{ErrInvalidState}, problems...)...)
+		}
+
+		medals := make([]medal.Model, 0, len(s.Medals()))
+		for _, m := range s.Medals() {
+			medals = append(medals, medal.NewModel(characterId, m.QuestId(), m.MedalId(), m.Category(), m.Status(), m.StartedAt(), m.CompletedAt()))
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			err := characterquest.Restore(l, tx)(characterId, s.Quests(), s.Progress())
+			if err != nil {
+				return err
+			}
+			err = medal.Restore(l, tx)(characterId, medals)
+			if err != nil {
+				return err
+			}
+			err = conversation.Restore(l, tx)(characterId, s.Conversations())
+			if err != nil {
+				return err
+			}
+			err = monsterbook.Restore(l, tx)(characterId, s.Cards())
+			if err != nil {
+				return err
+			}
+			return partyquest.Restore(l, tx)(characterId, s.PartyQuests())
+		})
+		if err != nil {
+			return err
+		}
+		l.Infof("Imported quest state of character %d from character %d.", characterId, s.CharacterId())
+		return nil
+	}
+}
+
+// validate lists each way in which the state disagrees with the quest data loaded for the tenant.
+func validate(t tenant.Model, s State) []error {
+	c := quest.GetCache(t)
+	problems := make([]error, 0)
+
+	statuses := make(map[uint16]string)
+	for _, m := range s.Quests() {
+		if _, ok := statuses[m.Id()]; ok {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d is listed more than once", m.Id())))
+			continue
+		}
+		statuses[m.Id()] = m.Status()
+		if _, err := c.GetQuest(m.Id()); err != nil {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d does not exist", m.Id())))
+		}
+		switch m.Status() {
+		case characterquest.StatusNotStarted, characterquest.StatusStarted, characterquest.StatusCompleted:
+		default:
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d has unknown status %s", m.Id(), m.Status())))
+		}
+	}
+
+	type kill struct {
+		questId uint16
+		mobId   uint32
+	}
+	kills := make(map[kill]bool)
+	for _, p := range s.Progress() {
+		k := kill{p.QuestId(), p.MobId()}
+		if kills[k] {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d kills of monster %d are listed more than once", p.QuestId(), p.MobId())))
+			continue
+		}
+		kills[k] = true
+
+		if status, ok := statuses[p.QuestId()]; !ok || status == characterquest.StatusNotStarted {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d has kills but has not been started", p.QuestId())))
+			continue
+		}
+		q, err := c.GetQuest(p.QuestId())
+		if err != nil {
+			continue
+		}
+		required, ok := q.KillsRequired(p.MobId())
+		if !ok {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d does not require kills of monster %d", p.QuestId(), p.MobId())))
+		} else if p.Count() > required {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d kills of monster %d exceed the %d required", p.QuestId(), p.MobId(), required)))
+		}
+	}
+
+	medals := make(map[uint16]bool)
+	for _, m := range s.Medals() {
+		if medals[m.QuestId()] {
+			problems = append(problems, errors.New(fmt.Sprintf("medal quest %d is listed more than once", m.QuestId())))
+			continue
+		}
+		medals[m.QuestId()] = true
+
+		q, err := c.GetQuest(m.QuestId())
+		if err != nil {
+			problems = append(problems, errors.New(fmt.Sprintf("medal quest %d does not exist", m.QuestId())))
+		} else if q.MedalId() == 0 || q.MedalId() != m.MedalId() {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d does not award medal %d", m.QuestId(), m.MedalId())))
+		}
+		if m.Status() != medal.StatusStarted && m.Status() != medal.StatusCompleted {
+			problems = append(problems, errors.New(fmt.Sprintf("medal quest %d has unknown status %s", m.QuestId(), m.Status())))
+		}
+	}
+
+	type phase struct {
+		questId uint16
+		phase   string
+	}
+	phases := make(map[phase]bool)
+	for _, o := range s.Conversations() {
+		p := phase{o.QuestId(), o.Phase()}
+		if phases[p] {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d %s conversation is listed more than once", o.QuestId(), o.Phase())))
+			continue
+		}
+		phases[p] = true
+
+		if _, err := c.GetQuest(o.QuestId()); err != nil {
+			problems = append(problems, errors.New(fmt.Sprintf("conversation quest %d does not exist", o.QuestId())))
+		}
+		if o.Phase() != conversation.PhaseStart && o.Phase() != conversation.PhaseComplete {
+			problems = append(problems, errors.New(fmt.Sprintf("quest %d has unknown conversation phase %s", o.QuestId(), o.Phase())))
+		}
+	}
+
+	cards := make(map[uint32]bool)
+	for _, bc := range s.Cards() {
+		if cards[bc.CardId()] {
+			problems = append(problems, errors.New(fmt.Sprintf("card %d is listed more than once", bc.CardId())))
+			continue
+		}
+		cards[bc.CardId()] = true
+
+		if !monsterbook.IsCard(bc.CardId()) {
+			problems = append(problems, errors.New(fmt.Sprintf("item %d is not a monster book card", bc.CardId())))
+		}
+		if bc.Level() == 0 || bc.Level() > monsterbook.MaxCardLevel {
+			problems = append(problems, errors.New(fmt.Sprintf("card %d has level %d outside of 1 to %d", bc.CardId(), bc.Level(), monsterbook.MaxCardLevel)))
+		}
+	}
+
+	partyQuests := make(map[uint32]bool)
+	for _, ps := range s.PartyQuests() {
+		if partyQuests[ps.PartyQuestId()] {
+			problems = append(problems, errors.New(fmt.Sprintf("party quest %d is listed more than once", ps.PartyQuestId())))
+			continue
+		}
+		partyQuests[ps.PartyQuestId()] = true
+
+		if _, err := partyquest.GetCache(t).GetPartyQuest(ps.PartyQuestId()); err != nil {
+			problems = append(problems, errors.New(fmt.Sprintf("party quest %d does not exist", ps.PartyQuestId())))
+		}
+		if ps.Clears() > ps.Tries() {
+			problems = append(problems, errors.New(fmt.Sprintf("party quest %d has %d clears from %d tries", ps.PartyQuestId(), ps.Clears(), ps.Tries())))
+		}
+		if ps.BestTime() > 0 && ps.Clears() == 0 {
+			problems = append(problems, errors.New(fmt.Sprintf("party quest %d has a best time but has not been cleared", ps.PartyQuestId())))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,132 @@
+package lifecycle
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/medal"
+	"atlas-quest/monsterbook"
+	"atlas-quest/partyquest"
+	"atlas-quest/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+const wzDir = "../../../wz"
+
+func setup(t *testing.T) (tenant.Model, *gorm.DB) {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	tm := tenant.NewModel("lifecycle-test", wzDir, "", "")
+	wz.GetFileCache(tm.Id()).Init(wzDir)
+	if err := quest.GetCache(tm).Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := partyquest.GetCache(tm).Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migrator := range []func(db *gorm.DB) error{characterquest.Migration, medal.Migration, conversation.Migration, monsterbook.Migration, partyquest.Migration} {
+		if err = migrator(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tm, db
+}
+
+func TestExportImportCardsAndPartyQuests(t *testing.T) {
+	tm, db := setup(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(exportQuestState)
+
+	for i := 0; i < 2; i++ {
+		if _, err := monsterbook.RecordCard(l, db)(1, 2380000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := monsterbook.RecordCard(l, db)(1, 2380001); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := partyquest.RecordAttempt(l, db, tm)(1, 1200, true, 300); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := partyquest.RecordAttempt(l, db, tm)(1, 1200, false, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Export(l, span, db)(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = extractState(makeStateBody(s, time.Now()).Attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Import(l, db, tm)(2, s); err != nil {
+		t.Fatal(err)
+	}
+
+	book, err := monsterbook.GetBook(l, db)(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(book) != 2 || book[2380000] != 2 || book[2380001] != 1 {
+		t.Fatalf("imported monster book = %v, want card 2380000 at 2 and 2380001 at 1", book)
+	}
+	ps, err := partyquest.GetStatsById(l, db)(2, 1200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Tries() != 2 || ps.Clears() != 1 || ps.BestTime() != 300 {
+		t.Fatalf("imported party quest stats = %d tries %d clears %d best, want 2 1 300", ps.Tries(), ps.Clears(), ps.BestTime())
+	}
+}
+
+func TestImportRejectsInvalidCardsAndPartyQuests(t *testing.T) {
+	tm, db := setup(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+
+	for _, tc := range []struct {
+		name        string
+		cards       []monsterbook.Card
+		partyQuests []partyquest.Stats
+	}{
+		{"not a card", []monsterbook.Card{monsterbook.NewCard(1, 4000000, 1)}, nil},
+		{"card level", []monsterbook.Card{monsterbook.NewCard(1, 2380000, monsterbook.MaxCardLevel+1)}, nil},
+		{"duplicate card", []monsterbook.Card{monsterbook.NewCard(1, 2380000, 1), monsterbook.NewCard(1, 2380000, 2)}, nil},
+		{"unknown party quest", nil, []partyquest.Stats{partyquest.NewStats(1, 9999, 1, 0, 0)}},
+		{"clears exceed tries", nil, []partyquest.Stats{partyquest.NewStats(1, 1200, 1, 2, 0)}},
+		{"best time without clear", nil, []partyquest.Stats{partyquest.NewStats(1, 1200, 1, 0, 300)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewState(1, nil, nil, nil, nil, tc.cards, tc.partyQuests)
+			err := Import(l, db, tm)(2, s)
+			if !errors.Is(err, ErrInvalidState) {
+				t.Fatalf("import = %v, want %v", err, ErrInvalidState)
+			}
+		})
+	}
+}
+
+func TestExtractStateRefusesVersion(t *testing.T) {
+	_, err := extractState(stateAttributes{Version: Version + 1})
+	if err == nil {
+		t.Fatalf("version %d was accepted", Version+1)
+	}
+}
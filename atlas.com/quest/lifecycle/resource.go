@@ -0,0 +1,136 @@
+package lifecycle
+
+import (
+	"atlas-quest/json"
+	"atlas-quest/rest"
+	"atlas-quest/rest/resource"
+	"atlas-quest/tenant"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	exportQuestState   = "export_quest_state"
+	importQuestState   = "import_quest_state"
+	consumeStatusEvent = "consume_character_status_event"
+)
+
+func InitResource(router *mux.Router, l logrus.FieldLogger, db *gorm.DB) {
+	r := router.PathPrefix("/characters/{characterId}/quest-state").Subrouter()
+	r.HandleFunc("/", registerExportQuestState(l, db)).Methods(http.MethodGet)
+	r.HandleFunc("/", rest.RequireAdmin(l, registerImportQuestState(l, db))).Methods(http.MethodPut)
+
+	er := router.PathPrefix("/events").Subrouter()
+	er.HandleFunc("/character-status", rest.RequireAdmin(l, registerConsumeStatusEvent(l, db))).Methods(http.MethodPost)
+}
+
+type CharacterIdHandler func(characterId uint32) http.HandlerFunc
+
+func ParseCharacterId(l logrus.FieldLogger, next CharacterIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		characterId, err := strconv.Atoi(mux.Vars(r)["characterId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse characterId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(characterId))(w, r)
+	}
+}
+
+func registerExportQuestState(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(exportQuestState, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					s, err := Export(l, span, t.Database(db))(characterId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to export quest state of character %d.", characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					w.WriteHeader(http.StatusOK)
+					err = json.ToJSON(stateDataContainer{Data: makeStateBody(s, time.Now())}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for character %d quest state.", characterId)
+					}
+				}
+			})
+		})
+	})
+}
+
+func registerImportQuestState(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(importQuestState, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					input := &stateDataContainer{}
+					err := json.FromJSON(input, r.Body)
+					if err != nil {
+						l.WithError(err).Errorf("Deserializing input.")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+
+					s, err := extractState(input.Data.Attributes)
+					if err != nil {
+						writeError(l, w, http.StatusBadRequest, err)
+						return
+					}
+
+					err = Import(l, t.Database(db), t)(characterId, s)
+					if errors.Is(err, ErrInvalidState) {
+						writeError(l, w, http.StatusUnprocessableEntity, err)
+						return
+					}
+					if err != nil {
+						l.WithError(err).Errorf("Unable to import quest state of character %d.", characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				}
+			})
+		})
+	})
+}
+
+func registerConsumeStatusEvent(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(consumeStatusEvent, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				e := statusEvent{}
+				err := json.FromJSON(&e, r.Body)
+				if err != nil {
+					l.WithError(err).Errorf("Deserializing input.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				err = handleStatusEvent(l, t.Database(db))(e)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to handle character %d status event of type %s.", e.CharacterId, e.Type)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+		})
+	})
+}
+
+func writeError(l logrus.FieldLogger, w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+	if err != nil {
+		l.WithError(err).Errorf("Writing error response.")
+	}
+}
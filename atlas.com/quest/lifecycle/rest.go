@@ -0,0 +1,139 @@
+package lifecycle
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/medal"
+	"atlas-quest/monsterbook"
+	"atlas-quest/partyquest"
+	"atlas-quest/quest/conversation"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func makeStateBody(s State, exportedAt time.Time) stateDataBody {
+	progress := make(map[uint16][]progressAttributes)
+	for _, p := range s.Progress() {
+		progress[p.QuestId()] = append(progress[p.QuestId()], progressAttributes{MobId: p.MobId(), Count: p.Count()})
+	}
+
+	quests := make([]questAttributes, 0, len(s.Quests()))
+	for _, m := range s.Quests() {
+		ps := progress[m.Id()]
+		if ps == nil {
+			ps = make([]progressAttributes, 0)
+		}
+		quests = append(quests, questAttributes{
+			QuestId:     m.Id(),
+			Status:      m.Status(),
+			StartedAt:   optionalTime(m.Started()),
+			CompletedAt: optionalTime(m.Completion()),
+			Info:        m.Info(),
+			Forfeits:    m.Forfeits(),
+			ForfeitedAt: optionalTime(m.Forfeited()),
+			Progress:    ps,
+		})
+	}
+
+	medals := make([]medalAttributes, 0, len(s.Medals()))
+	for _, m := range s.Medals() {
+		medals = append(medals, medalAttributes{
+			QuestId:     m.QuestId(),
+			MedalId:     m.MedalId(),
+			Category:    m.Category(),
+			Status:      m.Status(),
+			StartedAt:   optionalTime(m.StartedAt()),
+			CompletedAt: optionalTime(m.CompletedAt()),
+		})
+	}
+
+	conversations := make([]conversationAttributes, 0, len(s.Conversations()))
+	for _, o := range s.Conversations() {
+		conversations = append(conversations, conversationAttributes{
+			QuestId: o.QuestId(),
+			Phase:   o.Phase(),
+			Passed:  o.Passed(),
+		})
+	}
+
+	cards := make([]cardAttributes, 0, len(s.Cards()))
+	for _, c := range s.Cards() {
+		cards = append(cards, cardAttributes{CardId: c.CardId(), Level: c.Level()})
+	}
+
+	partyQuests := make([]partyQuestAttributes, 0, len(s.PartyQuests()))
+	for _, ps := range s.PartyQuests() {
+		partyQuests = append(partyQuests, partyQuestAttributes{
+			PartyQuestId: ps.PartyQuestId(),
+			Tries:        ps.Tries(),
+			Clears:       ps.Clears(),
+			BestTime:     ps.BestTime(),
+		})
+	}
+
+	return stateDataBody{
+		Id:   strconv.Itoa(int(s.CharacterId())),
+		Type: "quest-states",
+		Attributes: stateAttributes{
+			Version:       Version,
+			CharacterId:   s.CharacterId(),
+			ExportedAt:    exportedAt,
+			Quests:        quests,
+			Medals:        medals,
+			Conversations: conversations,
+			Cards:         cards,
+			PartyQuests:   partyQuests,
+		},
+	}
+}
+
+// extractState reads a quest state document. The character named is the one the document was exported from.
+func extractState(attr stateAttributes) (State, error) {
+	if attr.Version != Version {
+		return State{}, errors.New(fmt.Sprintf("unsupported quest state document version %d", attr.Version))
+	}
+
+	quests := make([]characterquest.Model, 0, len(attr.Quests))
+	progress := make([]characterquest.Progress, 0)
+	for _, q := range attr.Quests {
+		quests = append(quests, characterquest.NewModel(q.QuestId, q.Status, fromOptionalTime(q.StartedAt), fromOptionalTime(q.CompletedAt), q.Info, q.Forfeits, fromOptionalTime(q.ForfeitedAt)))
+		for _, p := range q.Progress {
+			progress = append(progress, characterquest.NewProgress(q.QuestId, p.MobId, p.Count))
+		}
+	}
+
+	medals := make([]medal.Model, 0, len(attr.Medals))
+	for _, m := range attr.Medals {
+		medals = append(medals, medal.NewModel(attr.CharacterId, m.QuestId, m.MedalId, m.Category, m.Status, fromOptionalTime(m.StartedAt), fromOptionalTime(m.CompletedAt)))
+	}
+
+	conversations := make([]conversation.Outcome, 0, len(attr.Conversations))
+	for _, c := range attr.Conversations {
+		conversations = append(conversations, conversation.NewOutcome(c.QuestId, c.Phase, c.Passed))
+	}
+	cards := make([]monsterbook.Card, 0, len(attr.Cards))
+	for _, c := range attr.Cards {
+		cards = append(cards, monsterbook.NewCard(attr.CharacterId, c.CardId, c.Level))
+	}
+
+	partyQuests := make([]partyquest.Stats, 0, len(attr.PartyQuests))
+	for _, ps := range attr.PartyQuests {
+		partyQuests = append(partyQuests, partyquest.NewStats(attr.CharacterId, ps.PartyQuestId, ps.Tries, ps.Clears, ps.BestTime))
+	}
+	return NewState(attr.CharacterId, quests, progress, medals, conversations, cards, partyQuests), nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func fromOptionalTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
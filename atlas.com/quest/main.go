@@ -1,18 +1,32 @@
 package main
 
 import (
+	characterquest "atlas-quest/character/quest"
 	"atlas-quest/database"
+	"atlas-quest/lifecycle"
 	"atlas-quest/logger"
+	"atlas-quest/medal"
+	"atlas-quest/monsterbook"
+	"atlas-quest/partyquest"
 	"atlas-quest/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/event"
+	"atlas-quest/quest/forfeit"
+	"atlas-quest/quest/sharing"
+	"atlas-quest/quest/world"
 	"atlas-quest/rest"
+	"atlas-quest/tenant"
 	"atlas-quest/tracing"
 	"atlas-quest/wz"
 	"context"
+	"github.com/sirupsen/logrus"
 	"io"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
+	"time"
 )
 
 const serviceName = "atlas-quest"
@@ -35,17 +49,77 @@ func main() {
 		}
 	}(tc)
 
-	wzDir := os.Getenv("WZ_DIR")
-	wz.GetFileCache().Init(wzDir)
+	if val, ok := os.LookupEnv("QUEST_TIMEZONE"); ok {
+		loc, err := time.LoadLocation(val)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to load timezone [%s], event dates will be read in local time.", val)
+		} else {
+			event.SetLocation(loc)
+		}
+	}
 
-	err = quest.GetCache().Init()
-	if err != nil {
-		l.WithError(err).Errorf("Unable to load quest cache.")
+	if val, ok := os.LookupEnv("QUEST_ADMIN_TOKEN"); ok && val != "" {
+		rest.SetAdminToken(val)
+	} else {
+		l.Warnf("QUEST_ADMIN_TOKEN is not set, administrative routes are disabled.")
+	}
+
+	tenantConfig, multiTenant := os.LookupEnv("TENANT_CONFIG")
+	if wzDir, ok := os.LookupEnv("WZ_DIR"); ok || !multiTenant {
+		permitScripts, _ := strconv.ParseBool(os.Getenv("QUEST_PERMIT_UNREGISTERED_SCRIPTS"))
+		tenant.GetRegistry().Add(tenant.NewModel(tenant.DefaultId, wzDir, os.Getenv("QUEST_SNAPSHOT"), "", tenant.SetPermitUnregisteredScripts(permitScripts)))
+	}
+	if multiTenant {
+		err = tenant.GetRegistry().Init(tenantConfig)
+		if err != nil {
+			l.WithError(err).Fatalf("Unable to load tenant configuration [%s].", tenantConfig)
+		}
+	}
+	if _, err = tenant.GetRegistry().Get(tenant.DefaultId); err == nil {
+		for _, t := range tenant.GetRegistry().GetAll() {
+			if t.Id() != tenant.DefaultId && t.DatabaseName() == os.Getenv("DB_NAME") {
+				l.Fatalf("Tenant [%s] shares the service database [%s] with the default tenant.", t.Id(), t.DatabaseName())
+			}
+		}
+	}
+
+	quest.RegisterDependentCache("party quest", func(t tenant.Model) error {
+		return partyquest.GetCache(t).Init()
+	})
+	quest.RegisterDependentCache("medal", func(t tenant.Model) error {
+		return medal.GetCache(t).Init()
+	})
+
+	strict, _ := strconv.ParseBool(os.Getenv("QUEST_LOAD_STRICT"))
+	for _, t := range tenant.GetRegistry().GetAll() {
+		loadTenantRules(l, t)
+		loadTenant(l, t, strict)
 	}
 
-	db := database.Connect(l, database.SetMigrations())
+	if val, ok := os.LookupEnv("WZ_WATCH_INTERVAL"); ok {
+		interval, err := time.ParseDuration(val)
+		if err != nil {
+			l.WithError(err).Errorf("Invalid WZ_WATCH_INTERVAL [%s], not watching for changes.", val)
+		} else {
+			for _, t := range tenant.GetRegistry().GetAll() {
+				watchTenant(l, ctx, wg, t, interval)
+			}
+		}
+	}
 
-	rest.CreateService(l, db, ctx, wg, "/ms/quest", quest.InitResource)
+	migrations := database.SetMigrations(partyquest.Migration, medal.Migration, characterquest.Migration, conversation.Migration, monsterbook.Migration, event.Migration)
+	db := database.Connect(l, migrations)
+	for _, t := range tenant.GetRegistry().GetAll() {
+		if t.DatabaseName() != "" {
+			tenant.GetRegistry().SetDatabase(t.Id(), database.Connect(l, migrations, database.SetDatabaseName(t.DatabaseName())))
+		}
+		err = event.GetRegistry().Init(t.Id(), t.Database(db))
+		if err != nil {
+			l.WithError(err).Errorf("Unable to load quest event window overrides of tenant [%s].", t.Id())
+		}
+	}
+
+	rest.CreateService(l, db, ctx, wg, "/ms/quest", quest.InitResource, partyquest.InitResource, medal.InitResource, monsterbook.InitResource, lifecycle.InitResource)
 
 	// trap sigterm or interrupt and gracefully shutdown the server
 	c := make(chan os.Signal, 1)
@@ -58,3 +132,71 @@ func main() {
 	wg.Wait()
 	l.Infoln("Service shutdown.")
 }
+
+// loadTenantRules reads the quest rule files of the tenant, falling back to those configured for the service.
+func loadTenantRules(l logrus.FieldLogger, t tenant.Model) {
+	tl := l.WithField("tenant", t.Id())
+	if val := tenantOrDefault(t.WorldConfig(), "QUEST_WORLD_CONFIG"); val != "" {
+		err := world.GetRegistry().Init(t.Id(), val)
+		if err != nil {
+			tl.WithError(err).Errorf("Unable to load world quest configuration [%s]. All quests are available in all worlds.", val)
+		}
+	}
+
+	if val := tenantOrDefault(t.KillSharingConfig(), "QUEST_KILL_SHARING_CONFIG"); val != "" {
+		err := sharing.GetRegistry().Init(t.Id(), val)
+		if err != nil {
+			tl.WithError(err).Errorf("Unable to load quest kill sharing configuration [%s]. Kills will be shared for all quests.", val)
+		}
+	}
+
+	if val := tenantOrDefault(t.ForfeitConfig(), "QUEST_FORFEIT_CONFIG"); val != "" {
+		err := forfeit.GetRegistry().Init(t.Id(), val)
+		if err != nil {
+			tl.WithError(err).Errorf("Unable to load quest forfeit rules [%s]. Only medal quests will be blocked from being forfeited.", val)
+		}
+	}
+}
+
+func tenantOrDefault(val string, key string) string {
+	if val != "" {
+		return val
+	}
+	return os.Getenv(key)
+}
+
+// loadTenant reads the WZ data of the tenant into its caches.
+func loadTenant(l logrus.FieldLogger, t tenant.Model, strict bool) {
+	tl := l.WithField("tenant", t.Id())
+	wz.GetFileCache(t.Id()).Init(t.WzDir())
+	if val := t.SnapshotPath(); val != "" {
+		err := wz.GetFileCache(t.Id()).UseSnapshot(val)
+		if err != nil {
+			tl.WithError(err).Warnf("Unable to use snapshot [%s], falling back to XML.", val)
+		} else if s, ok := wz.GetFileCache(t.Id()).Snapshot(); ok {
+			tl.Infof("Using snapshot [%s] with content hash [%s].", val, s.Hash())
+		}
+	}
+
+	err := quest.GetCache(t).Init(quest.SetStrict(strict))
+	if err != nil {
+		if strict {
+			tl.WithError(err).Fatalf("Unable to load quest cache.")
+		}
+		tl.WithError(err).Errorf("Unable to load quest cache.")
+	}
+	if ds := quest.GetCache(t).GetDiagnostics(); len(ds) > 0 {
+		tl.Warnf("Loaded quest cache with %d diagnostics. See /quests/diagnostics for details.", len(ds))
+	}
+	quest.ReportUnregisteredScripts(tl, t)
+
+	_ = quest.InitDependentCaches(tl, t)
+}
+
+// watchTenant reloads the caches of the tenant whenever its WZ directory changes.
+func watchTenant(l logrus.FieldLogger, ctx context.Context, wg *sync.WaitGroup, t tenant.Model, interval time.Duration) {
+	tl := l.WithField("tenant", t.Id())
+	wz.Watch(tl, ctx, wg, t.WzDir(), interval, func() error {
+		return quest.Reload(tl, t, false)
+	})
+}
@@ -0,0 +1,87 @@
+package medal
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// start records the character as working towards the medal, replacing any previous progress.
+func start(db *gorm.DB, characterId uint32, questId uint16, medalId uint32, category uint32) (Model, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(&entity{CharacterId: characterId, QuestId: questId}).First(&result).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result = entity{CharacterId: characterId, QuestId: questId}
+		} else if err != nil {
+			return err
+		}
+
+		result.MedalId = medalId
+		result.Category = category
+		result.Status = StatusStarted
+		result.StartedAt = time.Now()
+		result.CompletedAt = nil
+		return tx.Save(&result).Error
+	})
+	if err != nil {
+		return Model{}, err
+	}
+	return makeModel(result)
+}
+
+func complete(db *gorm.DB, characterId uint32, questId uint16) (Model, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(&entity{CharacterId: characterId, QuestId: questId}).First(&result).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result.Status = StatusCompleted
+		result.CompletedAt = &now
+		return tx.Save(&result).Error
+	})
+	if err != nil {
+		return Model{}, err
+	}
+	return makeModel(result)
+}
+
+func forfeit(db *gorm.DB, characterId uint32, questId uint16) error {
+	return db.Where(&entity{CharacterId: characterId, QuestId: questId, Status: StatusStarted}).Delete(&entity{}).Error
+}
+
+func deleteAll(db *gorm.DB, characterId uint32) error {
+	return db.Where(&entity{CharacterId: characterId}).Delete(&entity{}).Error
+}
+
+// restore replaces the character's medal quests with those given.
+func restore(db *gorm.DB, characterId uint32, ms []Model) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := deleteAll(tx, characterId)
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			e := entity{
+				CharacterId: characterId,
+				QuestId:     m.QuestId(),
+				MedalId:     m.MedalId(),
+				Category:    m.Category(),
+				Status:      m.Status(),
+				StartedAt:   m.StartedAt(),
+			}
+			if !m.CompletedAt().IsZero() {
+				completedAt := m.CompletedAt()
+				e.CompletedAt = &completedAt
+			}
+			err = tx.Create(&e).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package medal
+
+import "time"
+
+type dataContainer struct {
+	Data dataBody `json:"data"`
+}
+
+type dataListContainer struct {
+	Data []dataBody `json:"data"`
+}
+
+type dataBody struct {
+	Id         string     `json:"id"`
+	Type       string     `json:"type"`
+	Attributes attributes `json:"attributes"`
+}
+
+type attributes struct {
+	CharacterId uint32     `json:"characterId"`
+	QuestId     uint16     `json:"questId"`
+	MedalId     uint32     `json:"medalId"`
+	Category    uint32     `json:"category"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+type transitionInputDataContainer struct {
+	Data transitionInputDataBody `json:"data"`
+}
+
+type transitionInputDataBody struct {
+	Type       string                    `json:"type"`
+	Attributes transitionInputAttributes `json:"attributes"`
+}
+
+type transitionInputAttributes struct {
+	NpcId     uint32 `json:"npcId"`
+	Selection int    `json:"selection"`
+}
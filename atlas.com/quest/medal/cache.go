@@ -0,0 +1,50 @@
+package medal
+
+import (
+	"atlas-quest/tenant"
+	"sync"
+)
+
+type cache struct {
+	tenant    tenant.Model
+	exclusive map[uint16]bool
+	lock      sync.RWMutex
+}
+
+var caches = make(map[string]*cache)
+var cachesLock sync.Mutex
+
+// GetCache returns the medal cache of the tenant, creating an empty one should it not yet exist.
+func GetCache(t tenant.Model) *cache {
+	cachesLock.Lock()
+	defer cachesLock.Unlock()
+	if c, ok := caches[t.Id()]; ok {
+		return c
+	}
+	c := &cache{
+		tenant:    t,
+		exclusive: make(map[uint16]bool),
+		lock:      sync.RWMutex{},
+	}
+	caches[t.Id()] = c
+	return c
+}
+
+// Init reads the exclusive medal quest listing, replacing any previously loaded.
+func (c *cache) Init() error {
+	m, err := readExclusive(c.tenant)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.exclusive = m
+	c.lock.Unlock()
+	return nil
+}
+
+func (c *cache) IsExclusive(questId uint16) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.exclusive[questId]
+}
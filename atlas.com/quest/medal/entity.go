@@ -0,0 +1,25 @@
+package medal
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+type entity struct {
+	ID          uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId uint32 `gorm:"not null;uniqueIndex:idx_medal_quest_character"`
+	QuestId     uint16 `gorm:"not null;uniqueIndex:idx_medal_quest_character"`
+	MedalId     uint32 `gorm:"not null"`
+	Category    uint32 `gorm:"not null;default:0"`
+	Status      string `gorm:"not null"`
+	StartedAt   time.Time
+	CompletedAt *time.Time
+}
+
+func (e entity) TableName() string {
+	return "medal_quests"
+}
@@ -0,0 +1,57 @@
+package medal
+
+import "time"
+
+const (
+	StatusStarted   = "STARTED"
+	StatusCompleted = "COMPLETED"
+)
+
+// Model is a character's progress towards the medal awarded by a quest.
+type Model struct {
+	characterId uint32
+	questId     uint16
+	medalId     uint32
+	category    uint32
+	status      string
+	startedAt   time.Time
+	completedAt time.Time
+}
+
+// NewModel describes a character's progress towards a medal, as when restoring it from elsewhere. A zero completion
+// time is left unset.
+func NewModel(characterId uint32, questId uint16, medalId uint32, category uint32, status string, startedAt time.Time, completedAt time.Time) Model {
+	return Model{characterId: characterId, questId: questId, medalId: medalId, category: category, status: status, startedAt: startedAt, completedAt: completedAt}
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+func (m Model) QuestId() uint16 {
+	return m.questId
+}
+
+func (m Model) MedalId() uint32 {
+	return m.medalId
+}
+
+func (m Model) Category() uint32 {
+	return m.category
+}
+
+func (m Model) Status() string {
+	return m.status
+}
+
+func (m Model) StartedAt() time.Time {
+	return m.startedAt
+}
+
+func (m Model) CompletedAt() time.Time {
+	return m.completedAt
+}
+
+func (m Model) Earned() bool {
+	return m.status == StatusCompleted
+}
@@ -0,0 +1,181 @@
+package medal
+
+import (
+	"atlas-quest/character"
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/database"
+	"atlas-quest/quest"
+	"atlas-quest/tenant"
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotMedalQuest = errors.New("quest does not award a medal")
+	ErrExclusive     = errors.New("another medal quest of the category is in progress")
+	ErrNotStarted    = errors.New("medal quest has not been started")
+	ErrAlreadyEarned = errors.New("medal has already been earned")
+)
+
+func GetAll(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32) ([]Model, error) {
+	return func(characterId uint32) ([]Model, error) {
+		return database.ModelSliceProvider[Model, entity](db)(byCharacterEntityProvider(characterId), makeModel)()
+	}
+}
+
+func GetByStatus(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32, status string) ([]Model, error) {
+	return func(characterId uint32, status string) ([]Model, error) {
+		return database.ModelSliceProvider[Model, entity](db)(byCharacterAndStatusEntityProvider(characterId, status), makeModel)()
+	}
+}
+
+// GetEarned retrieves the medals the character has been awarded.
+func GetEarned(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) ([]Model, error) {
+	return func(characterId uint32) ([]Model, error) {
+		return GetByStatus(l, db)(characterId, StatusCompleted)
+	}
+}
+
+func GetById(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16) (Model, error) {
+	return func(characterId uint32, questId uint16) (Model, error) {
+		return database.ModelProvider[Model, entity](db)(byCharacterAndQuestEntityProvider(characterId, questId), makeModel)()
+	}
+}
+
+// Start begins the medal quest for the character, provided it meets the starting requirements of the quest. An
+// exclusive medal quest cannot be started while another exclusive quest of the same medal category is in progress. The
+// medal is tracked alongside the character's standing in the quest, which may already have been started elsewhere.
+func Start(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint16, npcId uint32, selection int) (Model, error) {
+	return func(characterId uint32, questId uint16, npcId uint32, selection int) (Model, error) {
+		q, err := quest.GetById(l, t)(uint32(questId))
+		if err != nil {
+			return Model{}, err
+		}
+		if q.MedalId() == 0 {
+			return Model{}, ErrNotMedalQuest
+		}
+
+		m, err := GetById(l, db)(characterId, questId)
+		if err == nil && m.Earned() {
+			return Model{}, ErrAlreadyEarned
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return Model{}, err
+		}
+
+		if GetCache(t).IsExclusive(questId) {
+			ms, err := GetByStatus(l, db)(characterId, StatusStarted)
+			if err != nil {
+				return Model{}, err
+			}
+			for _, o := range ms {
+				if o.QuestId() != questId && o.Category() == q.MedalCategory() && GetCache(t).IsExclusive(o.QuestId()) {
+					l.Debugf("Character %d cannot start medal quest %d while %d is in progress.", characterId, questId, o.QuestId())
+					return Model{}, ErrExclusive
+				}
+			}
+		}
+
+		_, err = quest.Start(l, span, db, t)(characterId, uint32(questId), npcId, selection)
+		if err != nil && !errors.Is(err, quest.ErrAlreadyStarted) {
+			return Model{}, err
+		}
+		if err == nil || m.Status() != StatusStarted {
+			return start(db, characterId, questId, q.MedalId(), q.MedalCategory())
+		}
+		return m, nil
+	}
+}
+
+// Complete finishes the medal quest the character has in progress, provided it meets the completion requirements of
+// the quest, and awards the character the medal. Should the quest have been completed without the medal being awarded,
+// the medal is awarded without completing the quest again.
+func Complete(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint16, npcId uint32, selection int) (Model, error) {
+	return func(characterId uint32, questId uint16, npcId uint32, selection int) (Model, error) {
+		q, err := quest.GetById(l, t)(uint32(questId))
+		if err != nil {
+			return Model{}, err
+		}
+		if q.MedalId() == 0 {
+			return Model{}, ErrNotMedalQuest
+		}
+
+		m, err := GetById(l, db)(characterId, questId)
+		if err == nil && m.Earned() {
+			return Model{}, ErrAlreadyEarned
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return Model{}, err
+		}
+
+		cq, err := characterquest.GetById(l, span, db)(characterId, questId)
+		if err != nil {
+			return Model{}, err
+		}
+		if cq.Status() != characterquest.StatusCompleted {
+			_, err = quest.Complete(l, span, db, t)(characterId, uint32(questId), npcId, selection)
+			if errors.Is(err, quest.ErrNotStarted) {
+				return Model{}, ErrNotStarted
+			} else if err != nil {
+				return Model{}, err
+			}
+		}
+		if m.Status() != StatusStarted {
+			_, err = start(db, characterId, questId, q.MedalId(), q.MedalCategory())
+			if err != nil {
+				return Model{}, err
+			}
+		}
+
+		err = character.GainItem(l, span, t)(characterId, q.MedalId(), 1)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to award medal %d to character %d.", q.MedalId(), characterId)
+			return Model{}, err
+		}
+		return complete(db, characterId, questId)
+	}
+}
+
+// Forfeit abandons a medal quest in progress, allowing another of its category to be started. The character's standing
+// in the quest is forfeited with it.
+func Forfeit(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint16) error {
+	return func(characterId uint32, questId uint16) error {
+		m, err := GetById(l, db)(characterId, questId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotStarted
+		} else if err != nil {
+			return err
+		}
+		if m.Earned() {
+			return ErrAlreadyEarned
+		}
+
+		_, err = quest.Forfeit(l, span, db, t)(characterId, uint32(questId))
+		if err != nil && !errors.Is(err, quest.ErrNotStarted) {
+			return err
+		}
+		return forfeit(db, characterId, questId)
+	}
+}
+
+// Delete removes all medal quests, earned or in progress, held for the character.
+func Delete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) error {
+	return func(characterId uint32) error {
+		err := deleteAll(db, characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to delete medal quests of character %d.", characterId)
+		}
+		return err
+	}
+}
+
+// Restore replaces all medal quests held for the character with those given.
+func Restore(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, ms []Model) error {
+	return func(characterId uint32, ms []Model) error {
+		err := restore(db, characterId, ms)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to restore medal quests of character %d.", characterId)
+		}
+		return err
+	}
+}
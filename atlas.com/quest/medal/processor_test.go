@@ -0,0 +1,109 @@
+package medal
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/event"
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+const wzDir = "../../../wz"
+
+func setup(t *testing.T) (tenant.Model, *gorm.DB) {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	tm := tenant.NewModel("medal-test", wzDir, "", "")
+	wz.GetFileCache(tm.Id()).Init(wzDir)
+	err := quest.GetCache(tm).Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migrator := range []func(db *gorm.DB) error{Migration, characterquest.Migration, conversation.Migration, event.Migration} {
+		if err = migrator(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tm, db
+}
+
+func TestCompleteRequiresQuestCompletion(t *testing.T) {
+	tm, db := setup(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(completeMedalQuest)
+
+	const characterId = uint32(1)
+	const questId = uint16(29001)
+	q, err := quest.GetById(l, tm)(uint32(questId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = characterquest.Start(l, db)(characterId, questId, characterquest.StatusNotStarted); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = start(db, characterId, questId, q.MedalId(), q.MedalCategory()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Complete(l, span, db, tm)(characterId, questId, 0, 0)
+	var re quest.RequirementError
+	if !errors.As(err, &re) {
+		t.Fatalf("completing without meeting requirements = %v, want a requirement error", err)
+	}
+	m, err := GetById(l, db)(characterId, questId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Earned() {
+		t.Fatalf("medal %d was earned without completing quest %d", m.MedalId(), questId)
+	}
+	cq, err := characterquest.GetById(l, span, db)(characterId, questId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cq.Status() != characterquest.StatusStarted {
+		t.Fatalf("quest status = %s, want %s", cq.Status(), characterquest.StatusStarted)
+	}
+
+	w := httptest.NewRecorder()
+	writeError(l, w, re)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("unmet requirements = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestStartUnknownQuest(t *testing.T) {
+	tm, db := setup(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(startMedalQuest)
+
+	_, err := Start(l, span, db, tm)(1, 65000, 0, 0)
+	if err == nil {
+		t.Fatal("started an unknown quest")
+	}
+	w := httptest.NewRecorder()
+	writeError(l, w, err)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown quest = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
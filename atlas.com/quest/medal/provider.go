@@ -0,0 +1,40 @@
+package medal
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/model"
+	"gorm.io/gorm"
+)
+
+func byCharacterEntityProvider(characterId uint32) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId})
+	}
+}
+
+func byCharacterAndStatusEntityProvider(characterId uint32, status string) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId, Status: status})
+	}
+}
+
+func byCharacterAndQuestEntityProvider(characterId uint32, questId uint16) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{CharacterId: characterId, QuestId: questId})
+	}
+}
+
+func makeModel(e entity) (Model, error) {
+	m := Model{
+		characterId: e.CharacterId,
+		questId:     e.QuestId,
+		medalId:     e.MedalId,
+		category:    e.Category,
+		status:      e.Status,
+		startedAt:   e.StartedAt,
+	}
+	if e.CompletedAt != nil {
+		m.completedAt = *e.CompletedAt
+	}
+	return m, nil
+}
@@ -0,0 +1,35 @@
+package medal
+
+import (
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"atlas-quest/xml"
+	"errors"
+	"strconv"
+)
+
+// readExclusive reads the ids of the medal quests which may not be in progress alongside another of their category.
+func readExclusive(t tenant.Model) (map[uint16]bool, error) {
+	root, err := wz.GetFileCache(t.Id()).GetNode("Exclusive.img.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uint16]bool)
+	md, err := root.ChildByName("medal")
+	if err != nil {
+		return results, nil
+	}
+	mp, ok := md.(xml.Parent)
+	if !ok {
+		return nil, errors.New("invalid xml structure")
+	}
+	for _, c := range mp.Children() {
+		id, err := strconv.Atoi(c.Name())
+		if err != nil {
+			return nil, err
+		}
+		results[uint16(id)] = true
+	}
+	return results, nil
+}
@@ -0,0 +1,230 @@
+package medal
+
+import (
+	"atlas-quest/json"
+	"atlas-quest/quest"
+	"atlas-quest/rest"
+	"atlas-quest/rest/resource"
+	"atlas-quest/tenant"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	getCharacterMedals      = "get_character_medals"
+	getCharacterMedalQuests = "get_character_medal_quests"
+	startMedalQuest         = "start_medal_quest"
+	completeMedalQuest      = "complete_medal_quest"
+	forfeitMedalQuest       = "forfeit_medal_quest"
+)
+
+func InitResource(router *mux.Router, l logrus.FieldLogger, db *gorm.DB) {
+	r := router.PathPrefix("/characters/{characterId}/medals").Subrouter()
+	r.HandleFunc("/", registerGetCharacterMedals(l, db)).Methods(http.MethodGet)
+
+	qr := router.PathPrefix("/characters/{characterId}/medalquests").Subrouter()
+	qr.HandleFunc("/", registerGetCharacterMedalQuests(l, db)).Methods(http.MethodGet)
+	qr.HandleFunc("/{id}/start", registerStartMedalQuest(l, db)).Methods(http.MethodPost)
+	qr.HandleFunc("/{id}/complete", registerCompleteMedalQuest(l, db)).Methods(http.MethodPost)
+	qr.HandleFunc("/{id}", registerForfeitMedalQuest(l, db)).Methods(http.MethodDelete)
+}
+
+type IdHandler func(questId uint16) http.HandlerFunc
+
+func ParseId(l logrus.FieldLogger, next IdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		questId, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse questId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint16(questId))(w, r)
+	}
+}
+
+type CharacterIdHandler func(characterId uint32) http.HandlerFunc
+
+func ParseCharacterId(l logrus.FieldLogger, next CharacterIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		characterId, err := strconv.Atoi(mux.Vars(r)["characterId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse characterId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(characterId))(w, r)
+	}
+}
+
+func registerGetCharacterMedals(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getCharacterMedals, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					ms, err := GetEarned(l, t.Database(db))(characterId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve medals for character %d.", characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					results := make([]dataBody, 0)
+					for _, m := range ms {
+						results = append(results, makeMedalBody(m))
+					}
+
+					w.WriteHeader(http.StatusOK)
+					err = json.ToJSON(dataListContainer{Data: results}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for character %d medals.", characterId)
+					}
+				}
+			})
+		})
+	})
+}
+
+func registerGetCharacterMedalQuests(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getCharacterMedalQuests, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					ms, err := GetAll(l, t.Database(db))(characterId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve medal quests for character %d.", characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					results := make([]dataBody, 0)
+					for _, m := range ms {
+						results = append(results, makeBody(m))
+					}
+
+					w.WriteHeader(http.StatusOK)
+					err = json.ToJSON(dataListContainer{Data: results}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for character %d medal quests.", characterId)
+					}
+				}
+			})
+		})
+	})
+}
+
+func registerStartMedalQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(startMedalQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint16) http.HandlerFunc {
+					return func(w http.ResponseWriter, r *http.Request) {
+						attr, err := parseTransition(r)
+						if err != nil {
+							l.WithError(err).Errorf("Deserializing input.")
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+
+						m, err := Start(l, span, t.Database(db), t)(characterId, questId, attr.NpcId, attr.Selection)
+						if err != nil {
+							writeError(l, w, err)
+							return
+						}
+
+						w.WriteHeader(http.StatusCreated)
+						err = json.ToJSON(dataContainer{Data: makeBody(m)}, w)
+						if err != nil {
+							l.WithError(err).Errorf("Writing response for character %d medal quest %d.", characterId, questId)
+						}
+					}
+				})
+			})
+		})
+	})
+}
+
+func registerCompleteMedalQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(completeMedalQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint16) http.HandlerFunc {
+					return func(w http.ResponseWriter, r *http.Request) {
+						attr, err := parseTransition(r)
+						if err != nil {
+							l.WithError(err).Errorf("Deserializing input.")
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+
+						m, err := Complete(l, span, t.Database(db), t)(characterId, questId, attr.NpcId, attr.Selection)
+						if err != nil {
+							writeError(l, w, err)
+							return
+						}
+
+						w.WriteHeader(http.StatusOK)
+						err = json.ToJSON(dataContainer{Data: makeBody(m)}, w)
+						if err != nil {
+							l.WithError(err).Errorf("Writing response for character %d medal quest %d.", characterId, questId)
+						}
+					}
+				})
+			})
+		})
+	})
+}
+
+func registerForfeitMedalQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(forfeitMedalQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint16) http.HandlerFunc {
+					return func(w http.ResponseWriter, _ *http.Request) {
+						err := Forfeit(l, span, t.Database(db), t)(characterId, questId)
+						if err != nil {
+							writeError(l, w, err)
+							return
+						}
+						w.WriteHeader(http.StatusNoContent)
+					}
+				})
+			})
+		})
+	})
+}
+
+// parseTransition reads the npc spoken with and the selection made. The body is optional, as medal quests seldom
+// require either.
+func parseTransition(r *http.Request) (transitionInputAttributes, error) {
+	input := &transitionInputDataContainer{}
+	err := json.FromJSON(input, r.Body)
+	if errors.Is(err, io.EOF) {
+		return transitionInputAttributes{}, nil
+	}
+	return input.Data.Attributes, err
+}
+
+func writeError(l logrus.FieldLogger, w http.ResponseWriter, err error) {
+	var nfe quest.NotFoundError
+	var re quest.RequirementError
+	switch {
+	case errors.Is(err, ErrNotMedalQuest), errors.Is(err, ErrNotStarted), errors.As(err, &nfe):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, ErrExclusive), errors.Is(err, ErrAlreadyEarned), errors.As(err, &re),
+		errors.Is(err, quest.ErrAlreadyCompleted), errors.Is(err, quest.ErrNotForfeitable):
+		w.WriteHeader(http.StatusConflict)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+	if err != nil {
+		l.WithError(err).Errorf("Writing error response.")
+	}
+}
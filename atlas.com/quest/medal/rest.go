@@ -0,0 +1,30 @@
+package medal
+
+import "strconv"
+
+func makeBody(m Model) dataBody {
+	b := dataBody{
+		Id:   strconv.Itoa(int(m.QuestId())),
+		Type: "medal-quests",
+		Attributes: attributes{
+			CharacterId: m.CharacterId(),
+			QuestId:     m.QuestId(),
+			MedalId:     m.MedalId(),
+			Category:    m.Category(),
+			Status:      m.Status(),
+			StartedAt:   m.StartedAt(),
+		},
+	}
+	if m.Earned() {
+		completedAt := m.CompletedAt()
+		b.Attributes.CompletedAt = &completedAt
+	}
+	return b
+}
+
+func makeMedalBody(m Model) dataBody {
+	b := makeBody(m)
+	b.Id = strconv.Itoa(int(m.MedalId()))
+	b.Type = "medals"
+	return b
+}
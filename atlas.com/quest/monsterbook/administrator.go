@@ -0,0 +1,50 @@
+package monsterbook
+
+import (
+	"errors"
+	"gorm.io/gorm"
+)
+
+// addCard records a pickup of the card, up to MaxCardLevel copies.
+func addCard(db *gorm.DB, characterId uint32, cardId uint32) (Card, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(&entity{CharacterId: characterId, CardId: cardId}).First(&result).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result = entity{CharacterId: characterId, CardId: cardId}
+		} else if err != nil {
+			return err
+		}
+
+		if result.Level >= MaxCardLevel {
+			return nil
+		}
+		result.Level += 1
+		return tx.Save(&result).Error
+	})
+	if err != nil {
+		return Card{}, err
+	}
+	return makeCard(result)
+}
+
+func deleteAll(db *gorm.DB, characterId uint32) error {
+	return db.Where(&entity{CharacterId: characterId}).Delete(&entity{}).Error
+}
+
+// restore replaces the character's cards with those given.
+func restore(db *gorm.DB, characterId uint32, cs []Card) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := deleteAll(tx, characterId)
+		if err != nil {
+			return err
+		}
+		for _, c := range cs {
+			err = tx.Create(&entity{CharacterId: characterId, CardId: c.CardId(), Level: c.Level()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
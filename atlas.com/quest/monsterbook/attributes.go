@@ -0,0 +1,33 @@
+package monsterbook
+
+type dataContainer struct {
+	Data dataBody `json:"data"`
+}
+
+type dataListContainer struct {
+	Data []dataBody `json:"data"`
+}
+
+type dataBody struct {
+	Id         string     `json:"id"`
+	Type       string     `json:"type"`
+	Attributes attributes `json:"attributes"`
+}
+
+type attributes struct {
+	CharacterId uint32 `json:"characterId"`
+	Level       uint32 `json:"level"`
+}
+
+type inputDataContainer struct {
+	Data inputDataBody `json:"data"`
+}
+
+type inputDataBody struct {
+	Type       string          `json:"type"`
+	Attributes inputAttributes `json:"attributes"`
+}
+
+type inputAttributes struct {
+	CardId uint32 `json:"cardId"`
+}
@@ -0,0 +1,18 @@
+package monsterbook
+
+import "gorm.io/gorm"
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+type entity struct {
+	ID          uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId uint32 `gorm:"not null;uniqueIndex:idx_monster_book_card_character"`
+	CardId      uint32 `gorm:"not null;uniqueIndex:idx_monster_book_card_character"`
+	Level       uint32 `gorm:"not null;default:0"`
+}
+
+func (e entity) TableName() string {
+	return "monster_book_cards"
+}
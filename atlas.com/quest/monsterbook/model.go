@@ -0,0 +1,32 @@
+package monsterbook
+
+// MaxCardLevel is the number of copies of a card which count towards the monster book. Further pickups are ignored.
+const MaxCardLevel = 5
+
+type Card struct {
+	characterId uint32
+	cardId      uint32
+	level       uint32
+}
+
+func NewCard(characterId uint32, cardId uint32, level uint32) Card {
+	return Card{characterId: characterId, cardId: cardId, level: level}
+}
+
+func (c Card) CharacterId() uint32 {
+	return c.characterId
+}
+
+func (c Card) CardId() uint32 {
+	return c.cardId
+}
+
+// Level is the number of copies of the card the character has collected, up to MaxCardLevel.
+func (c Card) Level() uint32 {
+	return c.level
+}
+
+// IsCard reports whether the item is a monster book card.
+func IsCard(itemId uint32) bool {
+	return itemId/10000 == 238
+}
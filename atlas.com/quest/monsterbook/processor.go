@@ -0,0 +1,78 @@
+package monsterbook
+
+import (
+	"atlas-quest/database"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func GetCards(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32) ([]Card, error) {
+	return func(characterId uint32) ([]Card, error) {
+		return database.ModelSliceProvider[Card, entity](db)(byCharacterEntityProvider(characterId), makeCard)()
+	}
+}
+
+// GetBook retrieves the character's card levels, keyed by card id.
+func GetBook(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) (map[uint32]uint32, error) {
+	return func(characterId uint32) (map[uint32]uint32, error) {
+		cs, err := GetCards(l, db)(characterId)
+		if err != nil {
+			return nil, err
+		}
+		results := make(map[uint32]uint32)
+		for _, c := range cs {
+			results[c.CardId()] = c.Level()
+		}
+		return results, nil
+	}
+}
+
+// CountCards is the number of distinct cards the character has collected.
+func CountCards(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) (uint32, error) {
+	return func(characterId uint32) (uint32, error) {
+		cs, err := GetCards(l, db)(characterId)
+		if err != nil {
+			return 0, err
+		}
+		return uint32(len(cs)), nil
+	}
+}
+
+// RecordCard ingests the pickup of a monster book card by the character.
+func RecordCard(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, cardId uint32) (Card, error) {
+	return func(characterId uint32, cardId uint32) (Card, error) {
+		if !IsCard(cardId) {
+			return Card{}, errors.New(fmt.Sprintf("item %d is not a monster book card", cardId))
+		}
+		c, err := addCard(db, characterId, cardId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to record card %d for character %d.", cardId, characterId)
+			return Card{}, err
+		}
+		return c, nil
+	}
+}
+
+// Delete removes every card the character has collected.
+func Delete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) error {
+	return func(characterId uint32) error {
+		err := deleteAll(db, characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to delete monster book of character %d.", characterId)
+		}
+		return err
+	}
+}
+
+// Restore replaces all cards held for the character with those given.
+func Restore(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, cs []Card) error {
+	return func(characterId uint32, cs []Card) error {
+		err := restore(db, characterId, cs)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to restore monster book of character %d.", characterId)
+		}
+		return err
+	}
+}
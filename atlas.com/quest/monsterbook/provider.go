@@ -0,0 +1,21 @@
+package monsterbook
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/model"
+	"gorm.io/gorm"
+)
+
+func byCharacterEntityProvider(characterId uint32) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId})
+	}
+}
+
+func makeCard(e entity) (Card, error) {
+	return Card{
+		characterId: e.CharacterId,
+		cardId:      e.CardId,
+		level:       e.Level,
+	}, nil
+}
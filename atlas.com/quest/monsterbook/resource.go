@@ -0,0 +1,107 @@
+package monsterbook
+
+import (
+	"atlas-quest/json"
+	"atlas-quest/rest"
+	"atlas-quest/rest/resource"
+	"atlas-quest/tenant"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+const (
+	getCharacterCards = "get_character_monster_book_cards"
+	createCard        = "create_character_monster_book_card"
+)
+
+func InitResource(router *mux.Router, l logrus.FieldLogger, db *gorm.DB) {
+	r := router.PathPrefix("/characters/{characterId}/monsterbook/cards").Subrouter()
+	r.HandleFunc("/", registerGetCharacterCards(l, db)).Methods(http.MethodGet)
+	r.HandleFunc("/", registerCreateCard(l, db)).Methods(http.MethodPost)
+}
+
+type CharacterIdHandler func(characterId uint32) http.HandlerFunc
+
+func ParseCharacterId(l logrus.FieldLogger, next CharacterIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		characterId, err := strconv.Atoi(mux.Vars(r)["characterId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse characterId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(characterId))(w, r)
+	}
+}
+
+func registerGetCharacterCards(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getCharacterCards, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					cs, err := GetCards(l, t.Database(db))(characterId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve monster book for character %d.", characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					results := make([]dataBody, 0)
+					for _, c := range cs {
+						results = append(results, makeBody(c))
+					}
+
+					w.WriteHeader(http.StatusOK)
+					err = json.ToJSON(dataListContainer{Data: results}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for character %d monster book.", characterId)
+					}
+				}
+			})
+		})
+	})
+}
+
+func registerCreateCard(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(createCard, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					input := &inputDataContainer{}
+					err := json.FromJSON(input, r.Body)
+					if err != nil {
+						l.WithError(err).Errorf("Deserializing input.")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+
+					cardId := input.Data.Attributes.CardId
+					if !IsCard(cardId) {
+						w.WriteHeader(http.StatusBadRequest)
+						err = json.ToJSON(&resource.GenericError{Message: "item is not a monster book card"}, w)
+						if err != nil {
+							l.WithError(err).Errorf("Writing error response.")
+						}
+						return
+					}
+
+					c, err := RecordCard(l, t.Database(db))(characterId, cardId)
+					if err != nil {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					w.WriteHeader(http.StatusCreated)
+					err = json.ToJSON(dataContainer{Data: makeBody(c)}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for character %d monster book card %d.", characterId, cardId)
+					}
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package monsterbook
+
+import "strconv"
+
+func makeBody(c Card) dataBody {
+	return dataBody{
+		Id:   strconv.Itoa(int(c.CardId())),
+		Type: "monster-book-cards",
+		Attributes: attributes{
+			CharacterId: c.CharacterId(),
+			Level:       c.Level(),
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package partyquest
+
+import (
+	"errors"
+	"gorm.io/gorm"
+)
+
+// recordAttempt adds a try to the character's stats for the party quest, creating them if necessary. A clear also
+// counts towards the clear total, and updates the best time when faster.
+func recordAttempt(db *gorm.DB, characterId uint32, partyQuestId uint32, cleared bool, seconds uint32) (Stats, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(&entity{CharacterId: characterId, PartyQuestId: partyQuestId}).First(&result).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result = entity{CharacterId: characterId, PartyQuestId: partyQuestId}
+		} else if err != nil {
+			return err
+		}
+
+		result.Tries += 1
+		if cleared {
+			result.Clears += 1
+			if seconds > 0 && (result.BestTime == 0 || seconds < result.BestTime) {
+				result.BestTime = seconds
+			}
+		}
+		return tx.Save(&result).Error
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+	return makeStats(result)
+}
+
+func deleteAll(db *gorm.DB, characterId uint32) error {
+	return db.Where(&entity{CharacterId: characterId}).Delete(&entity{}).Error
+}
+
+// restore replaces the character's party quest stats with those given.
+func restore(db *gorm.DB, characterId uint32, ss []Stats) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := deleteAll(tx, characterId)
+		if err != nil {
+			return err
+		}
+		for _, s := range ss {
+			err = tx.Create(&entity{CharacterId: characterId, PartyQuestId: s.PartyQuestId(), Tries: s.Tries(), Clears: s.Clears(), BestTime: s.BestTime()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package partyquest
+
+type dataContainer struct {
+	Data dataBody `json:"data"`
+}
+
+type dataListContainer struct {
+	Data []dataBody `json:"data"`
+}
+
+type dataBody struct {
+	Id         string     `json:"id"`
+	Type       string     `json:"type"`
+	Attributes attributes `json:"attributes"`
+}
+
+type attributes struct {
+	Name  string           `json:"name"`
+	Mark  string           `json:"mark"`
+	Ranks []rankAttributes `json:"ranks"`
+}
+
+type rankAttributes struct {
+	Grade      string                `json:"grade"`
+	Conditions []conditionAttributes `json:"conditions"`
+}
+
+type conditionAttributes struct {
+	Stat       string `json:"stat"`
+	Comparison string `json:"comparison"`
+	Value      uint32 `json:"value"`
+}
+
+type statsDataContainer struct {
+	Data statsDataBody `json:"data"`
+}
+
+type statsDataListContainer struct {
+	Data []statsDataBody `json:"data"`
+}
+
+type statsDataBody struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	Attributes statsAttributes `json:"attributes"`
+}
+
+type statsAttributes struct {
+	CharacterId uint32 `json:"characterId"`
+	Tries       uint32 `json:"tries"`
+	Clears      uint32 `json:"clears"`
+	ClearRate   uint32 `json:"clearRate"`
+	BestTime    uint32 `json:"bestTime"`
+	Rank        string `json:"rank"`
+}
+
+type attemptInputDataContainer struct {
+	Data attemptDataBody `json:"data"`
+}
+
+type attemptDataBody struct {
+	Type       string            `json:"type"`
+	Attributes attemptAttributes `json:"attributes"`
+}
+
+type attemptAttributes struct {
+	Cleared bool   `json:"cleared"`
+	Time    uint32 `json:"time"`
+}
@@ -0,0 +1,74 @@
+package partyquest
+
+import (
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type cache struct {
+	tenant      tenant.Model
+	partyQuests map[uint32]Model
+	lock        sync.RWMutex
+}
+
+var caches = make(map[string]*cache)
+var cachesLock sync.Mutex
+
+// GetCache returns the party quest cache of the tenant, creating an empty one should it not yet exist.
+func GetCache(t tenant.Model) *cache {
+	cachesLock.Lock()
+	defer cachesLock.Unlock()
+	if c, ok := caches[t.Id()]; ok {
+		return c
+	}
+	c := &cache{
+		tenant:      t,
+		partyQuests: make(map[uint32]Model),
+		lock:        sync.RWMutex{},
+	}
+	caches[t.Id()] = c
+	return c
+}
+
+// Init reads the party quest definitions, replacing any previously loaded.
+func (c *cache) Init() error {
+	pqs, err := readPartyQuests(c.tenant)
+	if err != nil {
+		return err
+	}
+
+	m := make(map[uint32]Model)
+	for _, pq := range pqs {
+		m[pq.Id()] = pq
+	}
+
+	c.lock.Lock()
+	c.partyQuests = m
+	c.lock.Unlock()
+	return nil
+}
+
+func (c *cache) GetPartyQuest(id uint32) (Model, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if val, ok := c.partyQuests[id]; ok {
+		return val, nil
+	}
+	return Model{}, errors.New(fmt.Sprintf("party quest %d not found", id))
+}
+
+func (c *cache) GetPartyQuests() []Model {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	results := make([]Model, 0, len(c.partyQuests))
+	for _, pq := range c.partyQuests {
+		results = append(results, pq)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Id() < results[j].Id()
+	})
+	return results
+}
@@ -0,0 +1,20 @@
+package partyquest
+
+import "gorm.io/gorm"
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+type entity struct {
+	ID           uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId  uint32 `gorm:"not null;uniqueIndex:idx_party_quest_character"`
+	PartyQuestId uint32 `gorm:"not null;uniqueIndex:idx_party_quest_character"`
+	Tries        uint32 `gorm:"not null;default:0"`
+	Clears       uint32 `gorm:"not null;default:0"`
+	BestTime     uint32 `gorm:"not null;default:0"`
+}
+
+func (e entity) TableName() string {
+	return "party_quest_stats"
+}
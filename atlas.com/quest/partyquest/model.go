@@ -0,0 +1,177 @@
+package partyquest
+
+const (
+	ComparisonLess  = "less"
+	ComparisonMore  = "more"
+	ComparisonEqual = "equal"
+
+	StatMinutes     = "min"
+	StatTries       = "try"
+	StatClearRate   = "CR"
+	StatVictoryRate = "VR"
+	StatHave        = "have"
+
+	RankS = "S"
+	RankA = "A"
+	RankB = "B"
+	RankC = "C"
+	RankD = "D"
+	RankF = "F"
+)
+
+// Model is a party quest definition, as found in PQuest.img.
+type Model struct {
+	id    uint32
+	name  string
+	mark  string
+	ranks []Rank
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) Name() string {
+	return m.name
+}
+
+func (m Model) Mark() string {
+	return m.mark
+}
+
+func (m Model) Ranks() []Rank {
+	return m.ranks
+}
+
+// Rank evaluates the stats provided against the rank rules, best first, and yields the first rank met. An empty rank
+// is returned when none are met.
+func (m Model) Rank(s Stats) string {
+	for _, r := range m.ranks {
+		if r.Met(s) {
+			return r.Grade()
+		}
+	}
+	return ""
+}
+
+type Rank struct {
+	grade      string
+	conditions []Condition
+}
+
+func (r Rank) Grade() string {
+	return r.grade
+}
+
+func (r Rank) Conditions() []Condition {
+	return r.conditions
+}
+
+func (r Rank) Met(s Stats) bool {
+	for _, c := range r.conditions {
+		if !c.Met(s) {
+			return false
+		}
+	}
+	return true
+}
+
+type Condition struct {
+	stat       string
+	comparison string
+	value      uint32
+}
+
+func (c Condition) Stat() string {
+	return c.stat
+}
+
+func (c Condition) Comparison() string {
+	return c.comparison
+}
+
+func (c Condition) Value() uint32 {
+	return c.value
+}
+
+// Met checks the condition against the stats. Bounds are inclusive, and a stat which is not yet known (such as the best
+// time of a party quest which has never been cleared) never satisfies a condition.
+func (c Condition) Met(s Stats) bool {
+	val, ok := s.Stat(c.stat)
+	if !ok {
+		return false
+	}
+	switch c.comparison {
+	case ComparisonLess:
+		return val <= c.value
+	case ComparisonMore:
+		return val >= c.value
+	case ComparisonEqual:
+		return val == c.value
+	}
+	return false
+}
+
+// Stats are a character's record for a single party quest.
+type Stats struct {
+	characterId  uint32
+	partyQuestId uint32
+	tries        uint32
+	clears       uint32
+	bestTime     uint32
+}
+
+func NewStats(characterId uint32, partyQuestId uint32, tries uint32, clears uint32, bestTime uint32) Stats {
+	return Stats{characterId: characterId, partyQuestId: partyQuestId, tries: tries, clears: clears, bestTime: bestTime}
+}
+
+func (s Stats) CharacterId() uint32 {
+	return s.characterId
+}
+
+func (s Stats) PartyQuestId() uint32 {
+	return s.partyQuestId
+}
+
+func (s Stats) Tries() uint32 {
+	return s.tries
+}
+
+func (s Stats) Clears() uint32 {
+	return s.clears
+}
+
+// BestTime is the fastest clear in seconds, or zero if never cleared.
+func (s Stats) BestTime() uint32 {
+	return s.bestTime
+}
+
+// ClearRate is the percentage of tries which ended in a clear.
+func (s Stats) ClearRate() uint32 {
+	if s.tries == 0 {
+		return 0
+	}
+	return s.clears * 100 / s.tries
+}
+
+// Stat yields the value of a stat as named in PQuest.img. Competitive party quests record a win as a clear, so the
+// victory rate is the clear rate. "have" is 1 once the party quest has been cleared at least once.
+func (s Stats) Stat(name string) (uint32, bool) {
+	switch name {
+	case StatMinutes:
+		if s.bestTime == 0 {
+			return 0, false
+		}
+		return (s.bestTime + 59) / 60, true
+	case StatTries:
+		return s.tries, true
+	case StatClearRate, StatVictoryRate:
+		return s.ClearRate(), true
+	case StatHave:
+		if s.clears > 0 {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,109 @@
+package partyquest
+
+import (
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"os"
+	"testing"
+)
+
+const wzDir = "../../../wz"
+
+func setup(t *testing.T) tenant.Model {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	tm := tenant.NewModel("partyquest-test", wzDir, "", "")
+	wz.GetFileCache(tm.Id()).Init(wzDir)
+	if err := GetCache(tm).Init(); err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestRank(t *testing.T) {
+	tm := setup(t)
+
+	for _, tc := range []struct {
+		name         string
+		partyQuestId uint32
+		tries        uint32
+		clears       uint32
+		bestTime     uint32
+		want         string
+	}{
+		// 1200: S needs at most 6 minutes, 100 tries and a 90% clear rate.
+		{"S at every bound", 1200, 100, 90, 360, RankS},
+		{"minutes rounded up past S", 1200, 100, 90, 361, RankA},
+		{"clear rate below S", 1200, 100, 89, 360, RankA},
+		{"C at every bound", 1200, 10, 3, 540, RankC},
+		{"never tried", 1200, 0, 0, 0, ""},
+		{"never cleared has no time", 1200, 5, 0, 0, ""},
+		// 1300 ranks by victory rate instead of clear rate, with no limit on minutes.
+		{"S by victory rate", 1300, 100, 40, 1000, RankS},
+		{"A by victory rate", 1300, 70, 21, 1000, RankA},
+		{"never won", 1300, 100, 0, 0, RankF},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pq, err := GetById(nil, tm)(tc.partyQuestId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pq.Rank(NewStats(1, tc.partyQuestId, tc.tries, tc.clears, tc.bestTime)); got != tc.want {
+				t.Errorf("Rank() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConditionMet(t *testing.T) {
+	s := NewStats(1, 1200, 10, 5, 300)
+	for _, tc := range []struct {
+		condition Condition
+		want      bool
+	}{
+		{Condition{stat: StatMinutes, comparison: ComparisonLess, value: 5}, true},
+		{Condition{stat: StatMinutes, comparison: ComparisonLess, value: 4}, false},
+		{Condition{stat: StatTries, comparison: ComparisonMore, value: 10}, true},
+		{Condition{stat: StatTries, comparison: ComparisonMore, value: 11}, false},
+		{Condition{stat: StatClearRate, comparison: ComparisonEqual, value: 50}, true},
+		{Condition{stat: StatVictoryRate, comparison: ComparisonEqual, value: 51}, false},
+		{Condition{stat: StatHave, comparison: ComparisonEqual, value: 1}, true},
+		{Condition{stat: "unknown", comparison: ComparisonMore, value: 0}, false},
+		{Condition{stat: StatTries, comparison: "unknown", value: 0}, false},
+	} {
+		if got := tc.condition.Met(s); got != tc.want {
+			t.Errorf("%s %s %d = %t, want %t", tc.condition.Stat(), tc.condition.Comparison(), tc.condition.Value(), got, tc.want)
+		}
+	}
+
+	unknown := Condition{stat: StatMinutes, comparison: ComparisonMore, value: 0}
+	if unknown.Met(NewStats(1, 1200, 1, 0, 0)) {
+		t.Errorf("minutes of a party quest never cleared met %s %s %d", unknown.Stat(), unknown.Comparison(), unknown.Value())
+	}
+}
+
+func TestStat(t *testing.T) {
+	for _, tc := range []struct {
+		stats Stats
+		name  string
+		want  uint32
+		ok    bool
+	}{
+		{NewStats(1, 1200, 1, 1, 60), StatMinutes, 1, true},
+		{NewStats(1, 1200, 1, 1, 61), StatMinutes, 2, true},
+		{NewStats(1, 1200, 1, 0, 0), StatMinutes, 0, false},
+		{NewStats(1, 1200, 3, 1, 60), StatTries, 3, true},
+		{NewStats(1, 1200, 3, 1, 60), StatClearRate, 33, true},
+		{NewStats(1, 1200, 3, 1, 60), StatVictoryRate, 33, true},
+		{NewStats(1, 1200, 0, 0, 0), StatClearRate, 0, true},
+		{NewStats(1, 1200, 3, 1, 60), StatHave, 1, true},
+		{NewStats(1, 1200, 3, 0, 0), StatHave, 0, true},
+		{NewStats(1, 1200, 3, 1, 60), "unknown", 0, false},
+	} {
+		got, ok := tc.stats.Stat(tc.name)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Stat(%s) of %d tries %d clears %d seconds = %d, %t, want %d, %t", tc.name, tc.stats.Tries(), tc.stats.Clears(), tc.stats.BestTime(), got, ok, tc.want, tc.ok)
+		}
+	}
+}
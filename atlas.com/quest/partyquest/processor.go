@@ -0,0 +1,107 @@
+package partyquest
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/tenant"
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func GetById(_ logrus.FieldLogger, t tenant.Model) func(partyQuestId uint32) (Model, error) {
+	return func(partyQuestId uint32) (Model, error) {
+		return GetCache(t).GetPartyQuest(partyQuestId)
+	}
+}
+
+func GetAll(_ logrus.FieldLogger, t tenant.Model) []Model {
+	return GetCache(t).GetPartyQuests()
+}
+
+func GetStats(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32) ([]Stats, error) {
+	return func(characterId uint32) ([]Stats, error) {
+		return database.ModelSliceProvider[Stats, entity](db)(byCharacterEntityProvider(characterId), makeStats)()
+	}
+}
+
+// GetStatsById retrieves the character's stats for the party quest. A character who has never attempted it has
+// empty stats.
+func GetStatsById(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32, partyQuestId uint32) (Stats, error) {
+	return func(characterId uint32, partyQuestId uint32) (Stats, error) {
+		s, err := database.ModelProvider[Stats, entity](db)(byCharacterAndPartyQuestEntityProvider(characterId, partyQuestId), makeStats)()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Stats{characterId: characterId, partyQuestId: partyQuestId}, nil
+		}
+		return s, err
+	}
+}
+
+func RecordAttempt(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(characterId uint32, partyQuestId uint32, cleared bool, seconds uint32) (Stats, error) {
+	return func(characterId uint32, partyQuestId uint32, cleared bool, seconds uint32) (Stats, error) {
+		_, err := GetById(l, t)(partyQuestId)
+		if err != nil {
+			return Stats{}, err
+		}
+		return recordAttempt(db, characterId, partyQuestId, cleared, seconds)
+	}
+}
+
+// GetRank computes the rank the character has achieved in the party quest.
+func GetRank(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(characterId uint32, partyQuestId uint32) (string, error) {
+	return func(characterId uint32, partyQuestId uint32) (string, error) {
+		pq, err := GetById(l, t)(partyQuestId)
+		if err != nil {
+			return "", err
+		}
+		s, err := GetStatsById(l, db)(characterId, partyQuestId)
+		if err != nil {
+			return "", err
+		}
+		return pq.Rank(s), nil
+	}
+}
+
+// CountRank counts the party quests in which the character has achieved the given rank.
+func CountRank(l logrus.FieldLogger, _ opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, grade string) (uint32, error) {
+	return func(characterId uint32, grade string) (uint32, error) {
+		ss, err := GetStats(l, db)(characterId)
+		if err != nil {
+			return 0, err
+		}
+
+		count := uint32(0)
+		for _, s := range ss {
+			pq, err := GetById(l, t)(s.PartyQuestId())
+			if err != nil {
+				continue
+			}
+			if pq.Rank(s) == grade {
+				count++
+			}
+		}
+		return count, nil
+	}
+}
+
+// Delete removes the character's stats for every party quest.
+func Delete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) error {
+	return func(characterId uint32) error {
+		err := deleteAll(db, characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to delete party quest stats of character %d.", characterId)
+		}
+		return err
+	}
+}
+
+// Restore replaces the character's stats for every party quest with those given.
+func Restore(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, ss []Stats) error {
+	return func(characterId uint32, ss []Stats) error {
+		err := restore(db, characterId, ss)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to restore party quest stats of character %d.", characterId)
+		}
+		return err
+	}
+}
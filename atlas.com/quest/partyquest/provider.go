@@ -0,0 +1,29 @@
+package partyquest
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/model"
+	"gorm.io/gorm"
+)
+
+func byCharacterEntityProvider(characterId uint32) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId})
+	}
+}
+
+func byCharacterAndPartyQuestEntityProvider(characterId uint32, partyQuestId uint32) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{CharacterId: characterId, PartyQuestId: partyQuestId})
+	}
+}
+
+func makeStats(e entity) (Stats, error) {
+	return Stats{
+		characterId:  e.CharacterId,
+		partyQuestId: e.PartyQuestId,
+		tries:        e.Tries,
+		clears:       e.Clears,
+		bestTime:     e.BestTime,
+	}, nil
+}
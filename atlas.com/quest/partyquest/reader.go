@@ -0,0 +1,95 @@
+package partyquest
+
+import (
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"atlas-quest/xml"
+	"errors"
+	"strconv"
+)
+
+var grades = []string{RankS, RankA, RankB, RankC, RankD, RankF}
+
+var comparisons = []string{ComparisonLess, ComparisonMore, ComparisonEqual}
+
+func readPartyQuests(t tenant.Model) ([]Model, error) {
+	root, err := wz.GetFileCache(t.Id()).GetNode("PQuest.img.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Model, 0)
+	for _, c := range root.Children() {
+		id, err := strconv.Atoi(c.Name())
+		if err != nil {
+			return nil, err
+		}
+		pq, ok := c.(xml.Parent)
+		if !ok {
+			return nil, errors.New("invalid xml structure")
+		}
+		m, err := createPartyQuest(uint32(id), pq)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	return results, nil
+}
+
+func createPartyQuest(id uint32, pq xml.Parent) (Model, error) {
+	m := Model{id: id, ranks: make([]Rank, 0)}
+	for _, c := range pq.Children() {
+		switch v := c.(type) {
+		case *xml.NullNode:
+			m.name = v.Name()
+		case *xml.StringNode:
+			if v.Name() == "mark" {
+				m.mark = v.Value()
+			} else {
+				// some entries carry their name as an empty string, rather than a null.
+				m.name = v.Name()
+			}
+		}
+	}
+
+	rd, err := pq.ChildByName("rank")
+	if err != nil {
+		return m, nil
+	}
+	rp, ok := rd.(xml.Parent)
+	if !ok {
+		return m, errors.New("invalid xml structure")
+	}
+
+	for _, grade := range grades {
+		gd, err := rp.ChildByName(grade)
+		if err != nil {
+			continue
+		}
+		gp, ok := gd.(xml.Parent)
+		if !ok {
+			return m, errors.New("invalid xml structure")
+		}
+		r := Rank{grade: grade, conditions: make([]Condition, 0)}
+		for _, comparison := range comparisons {
+			cd, err := gp.ChildByName(comparison)
+			if err != nil {
+				continue
+			}
+			cp, ok := cd.(xml.Parent)
+			if !ok {
+				return m, errors.New("invalid xml structure")
+			}
+			for _, sc := range cp.Children() {
+				val, err := xml.IntFromIntegerNode(sc)
+				if err != nil {
+					return m, err
+				}
+				r.conditions = append(r.conditions, Condition{stat: sc.Name(), comparison: comparison, value: uint32(val)})
+			}
+		}
+		m.ranks = append(m.ranks, r)
+	}
+	return m, nil
+}
@@ -0,0 +1,202 @@
+package partyquest
+
+import (
+	"atlas-quest/json"
+	"atlas-quest/rest"
+	"atlas-quest/tenant"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+const (
+	getPartyQuests          = "get_party_quests"
+	getPartyQuest           = "get_party_quest"
+	getCharacterPartyQuests = "get_character_party_quests"
+	getCharacterPartyQuest  = "get_character_party_quest"
+	createPartyQuestAttempt = "create_party_quest_attempt"
+)
+
+func InitResource(router *mux.Router, l logrus.FieldLogger, db *gorm.DB) {
+	r := router.PathPrefix("/partyquests").Subrouter()
+	r.HandleFunc("/", registerGetPartyQuests(l)).Methods(http.MethodGet)
+	r.HandleFunc("/{id}", registerGetPartyQuest(l)).Methods(http.MethodGet)
+
+	cr := router.PathPrefix("/characters/{characterId}/partyquests").Subrouter()
+	cr.HandleFunc("/", registerGetCharacterPartyQuests(l, db)).Methods(http.MethodGet)
+	cr.HandleFunc("/{id}", registerGetCharacterPartyQuest(l, db)).Methods(http.MethodGet)
+	cr.HandleFunc("/{id}/attempts", registerCreatePartyQuestAttempt(l, db)).Methods(http.MethodPost)
+}
+
+type IdHandler func(partyQuestId uint32) http.HandlerFunc
+
+func ParseId(l logrus.FieldLogger, next IdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		partyQuestId, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse partyQuestId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(partyQuestId))(w, r)
+	}
+}
+
+type CharacterIdHandler func(characterId uint32) http.HandlerFunc
+
+func ParseCharacterId(l logrus.FieldLogger, next CharacterIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		characterId, err := strconv.Atoi(mux.Vars(r)["characterId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse characterId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(characterId))(w, r)
+	}
+}
+
+func registerGetPartyQuests(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getPartyQuests, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				results := make([]dataBody, 0)
+				for _, pq := range GetAll(l, t) {
+					results = append(results, makeBody(pq))
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err := json.ToJSON(dataListContainer{Data: results}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for party quests.")
+				}
+			}
+		})
+	})
+}
+
+func registerGetPartyQuest(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getPartyQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseId(l, func(partyQuestId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					pq, err := GetById(l, t)(partyQuestId)
+					if err != nil {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+
+					w.WriteHeader(http.StatusOK)
+					err = json.ToJSON(dataContainer{Data: makeBody(pq)}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for party quest %d.", partyQuestId)
+					}
+				}
+			})
+		})
+	})
+}
+
+func registerGetCharacterPartyQuests(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getCharacterPartyQuests, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					ss, err := GetStats(l, t.Database(db))(characterId)
+					if err != nil {
+						l.WithError(err).Errorf("Unable to retrieve party quest stats for character %d.", characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					results := make([]statsDataBody, 0)
+					for _, s := range ss {
+						pq, err := GetById(l, t)(s.PartyQuestId())
+						if err != nil {
+							continue
+						}
+						results = append(results, makeStatsBody(pq, s))
+					}
+
+					w.WriteHeader(http.StatusOK)
+					err = json.ToJSON(statsDataListContainer{Data: results}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing response for character %d party quests.", characterId)
+					}
+				}
+			})
+		})
+	})
+}
+
+func registerGetCharacterPartyQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getCharacterPartyQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(partyQuestId uint32) http.HandlerFunc {
+					return func(w http.ResponseWriter, _ *http.Request) {
+						pq, err := GetById(l, t)(partyQuestId)
+						if err != nil {
+							w.WriteHeader(http.StatusNotFound)
+							return
+						}
+						s, err := GetStatsById(l, t.Database(db))(characterId, partyQuestId)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to retrieve party quest %d stats for character %d.", partyQuestId, characterId)
+							w.WriteHeader(http.StatusInternalServerError)
+							return
+						}
+
+						w.WriteHeader(http.StatusOK)
+						err = json.ToJSON(statsDataContainer{Data: makeStatsBody(pq, s)}, w)
+						if err != nil {
+							l.WithError(err).Errorf("Writing response for character %d party quest %d.", characterId, partyQuestId)
+						}
+					}
+				})
+			})
+		})
+	})
+}
+
+func registerCreatePartyQuestAttempt(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(createPartyQuestAttempt, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(partyQuestId uint32) http.HandlerFunc {
+					return func(w http.ResponseWriter, r *http.Request) {
+						input := &attemptInputDataContainer{}
+						err := json.FromJSON(input, r.Body)
+						if err != nil {
+							l.WithError(err).Errorf("Deserializing input.")
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+
+						pq, err := GetById(l, t)(partyQuestId)
+						if err != nil {
+							w.WriteHeader(http.StatusNotFound)
+							return
+						}
+						attr := input.Data.Attributes
+						s, err := RecordAttempt(l, t.Database(db), t)(characterId, partyQuestId, attr.Cleared, attr.Time)
+						if err != nil {
+							l.WithError(err).Errorf("Unable to record party quest %d attempt for character %d.", partyQuestId, characterId)
+							w.WriteHeader(http.StatusInternalServerError)
+							return
+						}
+
+						w.WriteHeader(http.StatusCreated)
+						err = json.ToJSON(statsDataContainer{Data: makeStatsBody(pq, s)}, w)
+						if err != nil {
+							l.WithError(err).Errorf("Writing response for character %d party quest %d.", characterId, partyQuestId)
+						}
+					}
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package partyquest
+
+import "strconv"
+
+func makeBody(m Model) dataBody {
+	ranks := make([]rankAttributes, 0)
+	for _, r := range m.Ranks() {
+		conditions := make([]conditionAttributes, 0)
+		for _, c := range r.Conditions() {
+			conditions = append(conditions, conditionAttributes{
+				Stat:       c.Stat(),
+				Comparison: c.Comparison(),
+				Value:      c.Value(),
+			})
+		}
+		ranks = append(ranks, rankAttributes{Grade: r.Grade(), Conditions: conditions})
+	}
+
+	return dataBody{
+		Id:   strconv.Itoa(int(m.Id())),
+		Type: "party-quests",
+		Attributes: attributes{
+			Name:  m.Name(),
+			Mark:  m.Mark(),
+			Ranks: ranks,
+		},
+	}
+}
+
+func makeStatsBody(m Model, s Stats) statsDataBody {
+	return statsDataBody{
+		Id:   strconv.Itoa(int(s.PartyQuestId())),
+		Type: "party-quest-stats",
+		Attributes: statsAttributes{
+			CharacterId: s.CharacterId(),
+			Tries:       s.Tries(),
+			Clears:      s.Clears(),
+			ClearRate:   s.ClearRate(),
+			BestTime:    s.BestTime(),
+			Rank:        m.Rank(s),
+		},
+	}
+}
@@ -15,21 +15,45 @@ const (
 	TypePopularity      = "POP"
 	TypeBuffItemId      = "BUFF"
 	TypePetSkill        = "PET_SKILL"
-	TypeNo              = "NO"
-	TypeYes             = "YES"
 	TypeNPC             = "NPC"
 	TypeMinimumLevel    = "MIN_LEVEL"
 	TypeNormalAutoStart = "NORMAL_AUTO_START"
 	TypePetTameness     = "PET_TAMENESS"
 	TypePetSpeed        = "PET_SPEED"
 	TypeInfo            = "INFO"
-	Type0               = "ZERO"
+	TypeQuest           = "QUEST"
+	TypeJob             = "JOB"
+	TypeMaximumLevel    = "MAX_LEVEL"
+	TypeFieldEnter      = "FIELD_ENTER"
+	TypeInterval        = "INTERVAL"
+	TypeStart           = "START"
+	TypeEnd             = "END"
+	TypeMessage         = "MESSAGE"
+	TypeMap             = "MAP"
+	TypeNPCAct          = "NPC_ACT"
+	TypeScript          = "SCRIPT"
 )
 
+// Item is an item granted or taken away by an item action.
+type Item struct {
+	id    uint32
+	count int32
+}
+
+func (i Item) Id() uint32 {
+	return i.id
+}
+
+func (i Item) Count() int32 {
+	return i.count
+}
+
 type Model struct {
-	theType Type
-	check   CheckFunc
-	run     RunFunc
+	theType    Type
+	items      []Item
+	changesJob bool
+	check      CheckFunc
+	run        RunFunc
 }
 
 type Type string
@@ -42,6 +66,17 @@ func (m Model) Type() Type {
 	return m.theType
 }
 
+// Items lists the items an item action grants, with a positive count, or takes away, with a negative count.
+func (m Model) Items() []Item {
+	return m.items
+}
+
+// ChangesJob reports whether the action advances the character to another job, rather than restricting the action to
+// a list of jobs.
+func (m Model) ChangesJob() bool {
+	return m.changesJob
+}
+
 func (m Model) Check() CheckFunc {
 	return m.check
 }
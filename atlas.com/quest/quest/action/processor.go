@@ -1,11 +1,15 @@
 package action
 
-import "atlas-quest/xml"
+import (
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/tenant"
+	"atlas-quest/xml"
+)
 
-func GetStarting(questId uint16, root xml.Noder) ([]Model, error) {
-	return get(questId, root, "0")
+func GetStarting(t tenant.Model, questId uint16, root xml.Noder) ([]Model, []diagnostic.Model, error) {
+	return get(t, questId, root, "0", diagnostic.PhaseStartAction)
 }
 
-func GetEnding(questId uint16, root xml.Noder) ([]Model, error) {
-	return get(questId, root, "1")
+func GetEnding(t tenant.Model, questId uint16, root xml.Noder) ([]Model, []diagnostic.Model, error) {
+	return get(t, questId, root, "1", diagnostic.PhaseCompleteAction)
 }
@@ -1,53 +1,190 @@
 package action
 
 import (
+	"atlas-quest/character/pet"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/quest/script"
+	"atlas-quest/tenant"
 	"atlas-quest/xml"
 	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
-func get(questId uint16, root xml.Noder, nodeName string) ([]Model, error) {
+func get(t tenant.Model, questId uint16, root xml.Noder, nodeName string, phase diagnostic.Phase) ([]Model, []diagnostic.Model, error) {
 	questData, ok := root.(xml.Parent)
 	if !ok {
-		return nil, errors.New("invalid xml structure")
+		return nil, nil, errors.New("invalid xml structure")
 	}
 
 	requirementsRoot, err := questData.ChildByName(nodeName)
 	if err != nil {
-		return nil, errors.New("invalid xml structure")
+		return nil, nil, errors.New("invalid xml structure")
 	}
 
 	rootAsParent, ok := requirementsRoot.(xml.Parent)
 	if !ok {
-		return nil, errors.New("invalid xml structure")
+		return nil, nil, errors.New("invalid xml structure")
 	}
 
 	results := make([]Model, 0)
+	diagnostics := make([]diagnostic.Model, 0)
 	for _, req := range rootAsParent.Children() {
+		if conversation.IsNode(req.Name()) {
+			continue
+		}
+		path := fmt.Sprintf("Act.img/%d/%s/%s", questId, nodeName, req.Name())
 		actType, err := getByWZName(req.Name())
 		if err != nil {
-			return nil, err
+			diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+			continue
 		}
 
 		m := Model{theType: actType}
-		check, run, err := getActionProducer(questId, actType, req)()
+		if actType == TypeItem {
+			items, err := getItems(req)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			m.items = items
+		}
+		if actType == TypeJob {
+			_, err = xml.IntFromIntegerNode(req)
+			m.changesJob = err == nil
+		}
+		check, run, err := getActionProducer(t, questId, actType, req)()
 		if err != nil {
-			return nil, err
+			diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+			continue
 		}
 		m.check = check
 		m.run = run
 		results = append(results, m)
 	}
+	return results, diagnostics, nil
+}
+
+func getItems(req xml.Noder) ([]Item, error) {
+	reqAsParent, ok := req.(xml.Parent)
+	if !ok {
+		return nil, errors.New("invalid xml structure")
+	}
+
+	results := make([]Item, 0)
+	for _, id := range reqAsParent.Children() {
+		item, ok := id.(xml.Parent)
+		if !ok {
+			return nil, errors.New("invalid xml structure")
+		}
+		iid, err := xml.GetInteger(item, "id")
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, Item{id: uint32(iid), count: xml.GetIntegerWithDefault(item, "count", 1)})
+	}
 	return results, nil
 }
 
 type actionProducer func() (CheckFunc, RunFunc, error)
 
-func getActionProducer(questId uint16, actType Type, req xml.Noder) actionProducer {
+func getActionProducer(t tenant.Model, questId uint16, actType Type, req xml.Noder) actionProducer {
+	switch actType {
+	case TypePetTameness:
+		return petTamenessAction(t, req)
+	case TypePetSkill:
+		return petSkillAction(t, req)
+	case TypePetSpeed:
+		return petSpeedAction(t, req)
+	}
 	return func() (CheckFunc, RunFunc, error) {
 		return nil, nil, nil
 	}
 }
 
+func errorActionProducer(err error) actionProducer {
+	return func() (CheckFunc, RunFunc, error) {
+		return nil, nil, err
+	}
+}
+
+func validActionProducer(check CheckFunc, run RunFunc) actionProducer {
+	return func() (CheckFunc, RunFunc, error) {
+		return check, run, nil
+	}
+}
+
+func checkPetSummoned(t tenant.Model) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ int) bool {
+		return func(characterId uint32, _ int) bool {
+			_, err := pet.GetLead(l, span, t)(characterId)
+			return err == nil
+		}
+	}
+}
+
+// runOnLeadPet applies f to the first pet the character has summoned.
+func runOnLeadPet(t tenant.Model, f func(l logrus.FieldLogger, span opentracing.Span, characterId uint32, p pet.Model) error) RunFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32, _ int) {
+		return func(characterId uint32, _ uint32, _ int) {
+			p, err := pet.GetLead(l, span, t)(characterId)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to locate pet of character %d.", characterId)
+				return
+			}
+			err = f(l, span, characterId, p)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to apply quest action to pet %d of character %d.", p.Id(), characterId)
+			}
+		}
+	}
+}
+
+// RunScript invokes the script named by the quest data once the quest has changed state, with the selection made in the
+// conversation.
+func RunScript(t tenant.Model, questId uint16, name string) RunFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32, extSelection int) {
+		return func(characterId uint32, npcId uint32, extSelection int) {
+			if !script.Run(l, span, db, t)(script.NewActionContext(name, questId, characterId, npcId, extSelection)) {
+				l.Warnf("Script [%s] of quest %d declined to act for character %d.", name, questId, characterId)
+			}
+		}
+	}
+}
+
+func petTamenessAction(t tenant.Model, req xml.Noder) actionProducer {
+	val, err := xml.IntFromIntegerNode(req)
+	if err != nil {
+		return errorActionProducer(err)
+	}
+	return validActionProducer(checkPetSummoned(t), runOnLeadPet(t, func(l logrus.FieldLogger, span opentracing.Span, characterId uint32, p pet.Model) error {
+		return pet.GainCloseness(l, span, t)(characterId, p.Id(), int16(val))
+	}))
+}
+
+func petSkillAction(t tenant.Model, req xml.Noder) actionProducer {
+	val, err := xml.IntFromIntegerNode(req)
+	if err != nil {
+		return errorActionProducer(err)
+	}
+	return validActionProducer(checkPetSummoned(t), runOnLeadPet(t, func(l logrus.FieldLogger, span opentracing.Span, characterId uint32, p pet.Model) error {
+		return pet.AddSkill(l, span, t)(characterId, p.Id(), uint16(val))
+	}))
+}
+
+func petSpeedAction(t tenant.Model, req xml.Noder) actionProducer {
+	val, err := xml.IntFromIntegerNode(req)
+	if err != nil {
+		return errorActionProducer(err)
+	}
+	return validActionProducer(checkPetSummoned(t), runOnLeadPet(t, func(l logrus.FieldLogger, span opentracing.Span, characterId uint32, p pet.Model) error {
+		return pet.ChangeSpeed(l, span, t)(characterId, p.Id(), int8(val))
+	}))
+}
+
 func getByWZName(name string) (Type, error) {
 	switch name {
 	case "exp":
@@ -60,16 +197,12 @@ func getByWZName(name string) (Type, error) {
 		return TypeSkill, nil
 	case "nextQuest":
 		return TypeNextQuest, nil
-	case "pop":
+	case "pop", "fame":
 		return TypePopularity, nil
 	case "buffItemID":
 		return TypeBuffItemId, nil
 	case "petskill":
 		return TypePetSkill, nil
-	case "no":
-		return TypeNo, nil
-	case "yes":
-		return TypeYes, nil
 	case "npc":
 		return TypeNPC, nil
 	case "lvmin":
@@ -82,8 +215,26 @@ func getByWZName(name string) (Type, error) {
 		return TypePetSpeed, nil
 	case "info":
 		return TypeInfo, nil
-	case "0":
-		return Type0, nil
+	case "quest":
+		return TypeQuest, nil
+	case "job":
+		return TypeJob, nil
+	case "lvmax":
+		return TypeMaximumLevel, nil
+	case "fieldEnter":
+		return TypeFieldEnter, nil
+	case "interval":
+		return TypeInterval, nil
+	case "start":
+		return TypeStart, nil
+	case "end":
+		return TypeEnd, nil
+	case "message":
+		return TypeMessage, nil
+	case "map":
+		return TypeMap, nil
+	case "npcAct":
+		return TypeNPCAct, nil
 	}
-	return "", errors.New("unknown type")
+	return "", errors.New(fmt.Sprintf("unknown type %s", name))
 }
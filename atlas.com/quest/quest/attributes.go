@@ -1,6 +1,370 @@
 package quest
 
+import "time"
+
+type dataContainer struct {
+	Data dataBody `json:"data"`
+}
+
+type dataBody struct {
+	Id         string     `json:"id"`
+	Type       string     `json:"type"`
+	Attributes attributes `json:"attributes"`
+}
+
 type attributes struct {
-	Name       string `json:"name"`
-	ParentName string `json:"parent_name"`
+	Name                string `json:"name"`
+	ParentName          string `json:"parent_name"`
+	TimeLimit           uint32 `json:"timeLimit"`
+	TimeLimit2          uint32 `json:"timeLimit2"`
+	AutoStart           bool   `json:"autoStart"`
+	AutoPreComplete     bool   `json:"autoPreComplete"`
+	AutoComplete        bool   `json:"autoComplete"`
+	AutoAccept          bool   `json:"autoAccept"`
+	Repeatable          bool   `json:"repeatable"`
+	MedalId             uint32 `json:"medalId"`
+	MedalCategory       uint32 `json:"medalCategory"`
+	Area                uint32 `json:"area"`
+	Order               uint32 `json:"order"`
+	SortKey             uint32 `json:"sortKey"`
+	QuestType           string `json:"questType"`
+	Summary             string `json:"summary"`
+	DemandSummary       string `json:"demandSummary"`
+	RewardSummary       string `json:"rewardSummary"`
+	SelectedMob         bool   `json:"selectedMob"`
+	SelectedSkillId     uint32 `json:"selectedSkillId"`
+	TimerUI             string `json:"timerUI"`
+	ShowLayerTag        string `json:"showLayerTag"`
+	OneShot             bool   `json:"oneShot"`
+	DailyPlayTime       uint32 `json:"dailyPlayTime"`
+	StartDescription    string `json:"startDescription"`
+	ProgressDescription string `json:"progressDescription"`
+	CompleteDescription string `json:"completeDescription"`
+}
+
+type listDataContainer struct {
+	Data  []sparseDataBody `json:"data"`
+	Meta  listMeta         `json:"meta"`
+	Links listLinks        `json:"links"`
+}
+
+// sparseDataBody carries only the attributes requested through a sparse fieldset.
+type sparseDataBody struct {
+	Id         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type listMeta struct {
+	Total  int `json:"total"`
+	Number int `json:"number"`
+	Size   int `json:"size"`
+}
+
+type listLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last"`
+}
+
+type diagnosticListDataContainer struct {
+	Data []diagnosticDataBody `json:"data"`
+}
+
+type diagnosticDataBody struct {
+	Id         string               `json:"id"`
+	Type       string               `json:"type"`
+	Attributes diagnosticAttributes `json:"attributes"`
+}
+
+type diagnosticAttributes struct {
+	QuestId uint16 `json:"questId"`
+	Phase   string `json:"phase"`
+	Path    string `json:"path"`
+	Error   string `json:"error"`
+	Skipped bool   `json:"skipped"`
+}
+
+type conversationDataContainer struct {
+	Data conversationDataBody `json:"data"`
+}
+
+type conversationDataBody struct {
+	Id         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Attributes conversationAttributes `json:"attributes"`
+}
+
+type conversationAttributes struct {
+	QuestId uint16           `json:"questId"`
+	Phase   string           `json:"phase"`
+	Steps   []stepAttributes `json:"steps"`
+	Ask     bool             `json:"ask"`
+	Yes     []string         `json:"yes"`
+	No      []string         `json:"no"`
+}
+
+type stepAttributes struct {
+	Text     string   `json:"text"`
+	Question bool     `json:"question"`
+	Answer   uint32   `json:"answer"`
+	Stop     []string `json:"stop"`
+}
+
+type answerInputDataContainer struct {
+	Data answerDataBody `json:"data"`
+}
+
+type answerDataBody struct {
+	Type       string           `json:"type"`
+	Attributes answerAttributes `json:"attributes"`
+}
+
+type answerAttributes struct {
+	Selections []uint32 `json:"selections"`
+	Accepted   bool     `json:"accepted"`
+}
+
+type resultDataContainer struct {
+	Data resultDataBody `json:"data"`
+}
+
+type resultDataBody struct {
+	Id         string           `json:"id"`
+	Type       string           `json:"type"`
+	Attributes resultAttributes `json:"attributes"`
+}
+
+type resultAttributes struct {
+	CharacterId uint32   `json:"characterId"`
+	QuestId     uint16   `json:"questId"`
+	Phase       string   `json:"phase"`
+	Passed      bool     `json:"passed"`
+	Step        int      `json:"step"`
+	Dialogue    []string `json:"dialogue"`
+}
+
+type scriptListDataContainer struct {
+	Data []scriptDataBody `json:"data"`
+}
+
+type scriptDataBody struct {
+	Id         string           `json:"id"`
+	Type       string           `json:"type"`
+	Attributes scriptAttributes `json:"attributes"`
+}
+
+type scriptAttributes struct {
+	Registered bool     `json:"registered"`
+	Quests     []uint16 `json:"quests"`
+}
+
+type eventWindowListDataContainer struct {
+	Data []eventWindowDataBody `json:"data"`
+}
+
+type eventWindowDataContainer struct {
+	Data eventWindowDataBody `json:"data"`
+}
+
+type eventWindowDataBody struct {
+	Id         string                `json:"id"`
+	Type       string                `json:"type"`
+	Attributes eventWindowAttributes `json:"attributes"`
+}
+
+type eventWindowAttributes struct {
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	Status     string `json:"status"`
+	Overridden bool   `json:"overridden"`
+}
+
+type itemUsageDataContainer struct {
+	Data itemUsageDataBody `json:"data"`
+}
+
+type itemUsageDataBody struct {
+	Id         string              `json:"id"`
+	Type       string              `json:"type"`
+	Attributes itemUsageAttributes `json:"attributes"`
+}
+
+type itemUsageAttributes struct {
+	QuestId       uint16 `json:"questId"`
+	StartCount    int32  `json:"startCount"`
+	CompleteCount int32  `json:"completeCount"`
+	Granted       int32  `json:"granted"`
+	StartGranted  int32  `json:"startGranted"`
+	Taken         int32  `json:"taken"`
+}
+
+type itemQuestsListDataContainer struct {
+	Data []itemQuestsDataBody `json:"data"`
+}
+
+type itemQuestsDataContainer struct {
+	Data itemQuestsDataBody `json:"data"`
+}
+
+type itemQuestsDataBody struct {
+	Id         string               `json:"id"`
+	Type       string               `json:"type"`
+	Attributes itemQuestsAttributes `json:"attributes"`
+}
+
+type itemQuestsAttributes struct {
+	Required []uint16 `json:"required"`
+	Rewarded []uint16 `json:"rewarded"`
+	Consumed []uint16 `json:"consumed"`
+}
+
+type dropEligibilityInputDataContainer struct {
+	Data dropEligibilityInputDataBody `json:"data"`
+}
+
+type dropEligibilityInputDataBody struct {
+	Type       string                    `json:"type"`
+	Attributes dropEligibilityAttributes `json:"attributes"`
+}
+
+type dropEligibilityDataContainer struct {
+	Data dropEligibilityDataBody `json:"data"`
+}
+
+type dropEligibilityDataBody struct {
+	Id         string                    `json:"id"`
+	Type       string                    `json:"type"`
+	Attributes dropEligibilityAttributes `json:"attributes"`
+}
+
+type dropEligibilityAttributes struct {
+	ItemIds []uint32 `json:"itemIds"`
+}
+
+type progressListDataContainer struct {
+	Data []progressDataBody `json:"data"`
+}
+
+type progressDataBody struct {
+	Id         string             `json:"id"`
+	Type       string             `json:"type"`
+	Attributes progressAttributes `json:"attributes"`
+}
+
+type progressAttributes struct {
+	CharacterId uint32 `json:"characterId"`
+	QuestId     uint16 `json:"questId"`
+	MobId       uint32 `json:"mobId"`
+	Count       uint32 `json:"count"`
+	Required    uint32 `json:"required"`
+	Progress    string `json:"progress"`
+}
+
+type killInputDataContainer struct {
+	Data killInputDataBody `json:"data"`
+}
+
+type killInputDataBody struct {
+	Type       string              `json:"type"`
+	Attributes killInputAttributes `json:"attributes"`
+}
+
+type killInputAttributes struct {
+	MobId uint32                  `json:"mobId"`
+	MapId uint32                  `json:"mapId"`
+	Party []partyMemberAttributes `json:"party"`
+}
+
+type partyMemberAttributes struct {
+	CharacterId uint32 `json:"characterId"`
+	MapId       uint32 `json:"mapId"`
+}
+
+type forfeitureDataContainer struct {
+	Data forfeitureDataBody `json:"data"`
+}
+
+type forfeitureDataBody struct {
+	Id         string               `json:"id"`
+	Type       string               `json:"type"`
+	Attributes forfeitureAttributes `json:"attributes"`
+}
+
+type forfeitureAttributes struct {
+	Forfeits    uint32                  `json:"forfeits"`
+	AvailableAt *time.Time              `json:"availableAt,omitempty"`
+	Removed     []removedItemAttributes `json:"removed"`
+}
+
+type removedItemAttributes struct {
+	ItemId   uint32 `json:"itemId"`
+	Quantity uint32 `json:"quantity"`
+}
+
+type eventWindowInputDataContainer struct {
+	Data eventWindowInputDataBody `json:"data"`
+}
+
+type eventWindowInputDataBody struct {
+	Type       string                     `json:"type"`
+	Attributes eventWindowInputAttributes `json:"attributes"`
+}
+
+type eventWindowInputAttributes struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type transitionInputDataContainer struct {
+	Data transitionInputDataBody `json:"data"`
+}
+
+type transitionInputDataBody struct {
+	Type       string                    `json:"type"`
+	Attributes transitionInputAttributes `json:"attributes"`
+}
+
+type transitionInputAttributes struct {
+	NpcId     uint32 `json:"npcId"`
+	Selection int    `json:"selection"`
+}
+
+type statusDataContainer struct {
+	Data statusDataBody `json:"data"`
+}
+
+type statusDataBody struct {
+	Id         string           `json:"id"`
+	Type       string           `json:"type"`
+	Attributes statusAttributes `json:"attributes"`
+}
+
+type statusAttributes struct {
+	CharacterId uint32     `json:"characterId"`
+	QuestId     uint16     `json:"questId"`
+	Status      string     `json:"status"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Info        string     `json:"info"`
+}
+
+type eligibilityDataContainer struct {
+	Data eligibilityDataBody `json:"data"`
+}
+
+type eligibilityDataBody struct {
+	Id         string                `json:"id"`
+	Type       string                `json:"type"`
+	Attributes eligibilityAttributes `json:"attributes"`
+}
+
+type eligibilityAttributes struct {
+	Status        string   `json:"status"`
+	CanStart      bool     `json:"canStart"`
+	CanComplete   bool     `json:"canComplete"`
+	UnmetStart    []string `json:"unmetStart"`
+	UnmetComplete []string `json:"unmetComplete"`
 }
@@ -1,56 +1,280 @@
 package quest
 
 import (
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 )
 
+// snapshot is an immutable view of the loaded quest data. Reloads build a new snapshot off to the side and swap it in,
+// so readers holding the previous one are unaffected.
+type snapshot struct {
+	quests      map[uint16]Model
+	diagnostics []diagnostic.Model
+	scripts     map[string][]uint16
+	index       *index
+	loadedAt    time.Time
+}
+
 type cache struct {
-	quests map[uint16]Model
-	lock   sync.RWMutex
+	tenant     tenant.Model
+	current    *snapshot
+	conf       *configuration
+	lock       sync.RWMutex
+	reloadLock sync.Mutex
 }
 
-var c *cache
-var once sync.Once
+var caches = make(map[string]*cache)
+var cachesLock sync.Mutex
 
-func GetCache() *cache {
-	once.Do(func() {
-		c = &cache{
-			quests: make(map[uint16]Model, 0),
-			lock:   sync.RWMutex{},
-		}
-	})
+// GetCache returns the quest cache of the tenant, creating an empty one should it not yet exist.
+func GetCache(t tenant.Model) *cache {
+	cachesLock.Lock()
+	defer cachesLock.Unlock()
+	if c, ok := caches[t.Id()]; ok {
+		return c
+	}
+	c := &cache{
+		tenant: t,
+		current: &snapshot{
+			quests:      make(map[uint16]Model, 0),
+			diagnostics: make([]diagnostic.Model, 0),
+			scripts:     make(map[string][]uint16),
+			index:       newIndex(make(map[uint16]Model)),
+		},
+		conf: &configuration{},
+		lock: sync.RWMutex{},
+	}
+	caches[t.Id()] = c
 	return c
 }
 
-func (c *cache) Init() error {
-	quests, err := readQuests()
+type configuration struct {
+	strict bool
+}
+
+type Configurator func(c *configuration)
+
+// SetStrict causes loading to fail on the first quest which cannot be loaded cleanly, rather than skipping it.
+func SetStrict(strict bool) Configurator {
+	return func(c *configuration) {
+		c.strict = strict
+	}
+}
+
+func (c *cache) Init(configurators ...Configurator) error {
+	conf := &configuration{}
+	for _, configurator := range configurators {
+		configurator(conf)
+	}
+
+	c.lock.Lock()
+	c.conf = conf
+	c.lock.Unlock()
+	return c.Reload()
+}
+
+// RegressionError is returned when reloaded quest data lacks quests present in the data in use. This typically
+// indicates files which were only partially copied into place.
+type RegressionError struct {
+	Quests        int
+	CurrentQuests int
+	Missing       []uint16
+}
+
+func (e RegressionError) Error() string {
+	return fmt.Sprintf("reloaded quest data regressed: %d quests loaded, %d in use, %d missing", e.Quests, e.CurrentQuests, len(e.Missing))
+}
+
+// Reload reads the quest data from disk into a fresh snapshot, and swaps it in once validated. Data which lacks any of
+// the quests of the snapshot in use is refused. On failure the previously loaded snapshot
+// remains in use.
+func (c *cache) Reload() error {
+	return c.reload(false)
+}
+
+// ForceReload is Reload, without refusing data which regresses from the snapshot in use. It is meant for deliberate
+// changes, such as the removal of quests.
+func (c *cache) ForceReload() error {
+	return c.reload(true)
+}
+
+func (c *cache) reload(force bool) error {
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	c.lock.RLock()
+	conf := c.conf
+	c.lock.RUnlock()
+
+	quests, diagnostics, err := readQuests(c.tenant, conf.strict)
 	if err != nil {
 		return err
 	}
+	if len(quests) == 0 {
+		return errors.New("no quests loaded")
+	}
+	if current := c.snapshot(); !force && !current.loadedAt.IsZero() {
+		if missing := missingQuests(current.quests, quests); len(missing) > 0 {
+			return RegressionError{
+				Quests:        len(quests),
+				CurrentQuests: len(current.quests),
+				Missing:       missing,
+			}
+		}
+	}
 
-	c.lock.Lock()
+	s := &snapshot{
+		quests:      make(map[uint16]Model, len(quests)),
+		diagnostics: diagnostics,
+		scripts:     make(map[string][]uint16),
+		loadedAt:    time.Now(),
+	}
 	for _, q := range quests {
-		c.quests[q.Id()] = q
+		s.quests[q.Id()] = q
+		for _, name := range q.Scripts() {
+			s.scripts[name] = append(s.scripts[name], q.Id())
+		}
 	}
+
+	s.index = newIndex(s.quests)
+
+	c.lock.Lock()
+	c.current = s
 	c.lock.Unlock()
 	return nil
 }
 
-//func (c *cache) GetFile(id uint32) (*Model, error) {
-//	c.lock.RLock()
-//	if val, ok := c.quests[id]; ok {
-//		c.lock.RUnlock()
-//		return &val, nil
-//	} else {
-//		c.lock.RUnlock()
-//		c.lock.Lock()
-//		s, err := readStatistics(id)
-//		if err != nil {
-//			c.lock.Unlock()
-//			return nil, err
-//		}
-//		c.quests[id] = *s
-//		c.lock.Unlock()
-//		return s, nil
-//	}
-//}
+// missingQuests is the ids of the quests in use which are absent from those loaded, in ascending order.
+func missingQuests(current map[uint16]Model, loaded []Model) []uint16 {
+	ids := make(map[uint16]bool, len(loaded))
+	for _, q := range loaded {
+		ids[q.Id()] = true
+	}
+	results := make([]uint16, 0)
+	for id := range current {
+		if !ids[id] {
+			results = append(results, id)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i] < results[j]
+	})
+	return results
+}
+
+func (c *cache) snapshot() *snapshot {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.current
+}
+
+func (c *cache) LoadedAt() time.Time {
+	return c.snapshot().loadedAt
+}
+
+func (c *cache) GetDiagnostics() []diagnostic.Model {
+	return c.snapshot().diagnostics
+}
+
+// GetScripts returns the names of the scripts referenced by the loaded quests, mapped to the quests referencing them.
+func (c *cache) GetScripts() map[string][]uint16 {
+	return c.snapshot().scripts
+}
+
+func (c *cache) GetQuests() []Model {
+	qs := c.snapshot().quests
+	results := make([]Model, 0, len(qs))
+	for _, q := range qs {
+		results = append(results, q)
+	}
+	return results
+}
+
+// Search returns the quests satisfying all the criteria, in ascending id order.
+func (c *cache) Search(filters ...Criteria) []Model {
+	conf := &criteria{}
+	for _, cr := range filters {
+		cr(conf)
+	}
+
+	s := c.snapshot()
+	results := make([]Model, 0)
+	for _, id := range s.index.candidates(conf) {
+		if q := s.quests[id]; conf.matches(q) {
+			results = append(results, q)
+		}
+	}
+	return results
+}
+
+// GetItemQuests returns the quests which require, reward or consume the item.
+func (c *cache) GetItemQuests(itemId uint32) ItemQuests {
+	return c.snapshot().index.itemQuests(itemId)
+}
+
+// GetCompletionCounts returns the quantity of the item each quest requires for completion, keyed by quest id.
+func (c *cache) GetCompletionCounts(itemId uint32) map[uint16]int32 {
+	s := c.snapshot()
+	results := make(map[uint16]int32)
+	for _, id := range s.index.byRequiredItem[itemId] {
+		q := s.quests[id]
+		if u, ok := q.ItemUsage(itemId); ok && u.CompleteCount() > 0 {
+			results[id] = u.CompleteCount()
+		}
+	}
+	return results
+}
+
+// GetExclusiveItems returns, in ascending order, the quest items no quest other than the one given makes use of. Only
+// items handed out on starting the quest, or required or consumed by it, are considered; those granted on completion
+// are the character's to keep.
+func (c *cache) GetExclusiveItems(questId uint16) []uint32 {
+	s := c.snapshot()
+	results := make([]uint32, 0)
+	q, ok := s.quests[questId]
+	if !ok {
+		return results
+	}
+	for _, ids := range [][]uint32{q.StartItems(), q.RequiredItems(), q.ConsumedItems()} {
+		for _, it := range ids {
+			if IsQuestItem(it) && s.index.itemQuests(it).UsedOnlyBy(questId) {
+				results = appendItem(results, it)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i] < results[j]
+	})
+	return results
+}
+
+// GetSkillBooks returns the skill and mastery books granted by quests, in ascending item id order.
+func (c *cache) GetSkillBooks() []ItemQuests {
+	i := c.snapshot().index
+	results := make([]ItemQuests, 0, len(i.skillBooks))
+	for _, it := range i.skillBooks {
+		results = append(results, i.itemQuests(it))
+	}
+	return results
+}
+
+func (c *cache) GetQuest(id uint16) (Model, error) {
+	if val, ok := c.snapshot().quests[id]; ok {
+		return val, nil
+	}
+	return Model{}, NotFoundError{Id: uint32(id)}
+}
+
+// NotFoundError reports a quest absent from the loaded data.
+type NotFoundError struct {
+	Id uint32
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("quest %d not found", e.Id)
+}
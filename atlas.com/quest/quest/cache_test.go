@@ -0,0 +1,161 @@
+package quest
+
+import (
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyQuestData copies the bundled quest data to a directory the test may modify.
+func copyQuestData(t *testing.T) string {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	dir := t.TempDir()
+	es, err := wz.Read(wzDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range es {
+		b, err := os.ReadFile(e.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, e.Name()), b, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func rewrite(t *testing.T, path string, old string, new string) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(strings.ReplaceAll(string(b), old, new)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadRefusesRegression(t *testing.T) {
+	dir := copyQuestData(t)
+	tm := tenant.NewModel("reload-test", dir, "", "")
+	wz.GetFileCache(tm.Id()).Init(dir)
+	c := GetCache(tm)
+	err := c.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	quests := len(c.GetQuests())
+
+	err = c.Reload()
+	if err != nil {
+		t.Fatalf("reloading unchanged data failed: %v", err)
+	}
+
+	rewrite(t, filepath.Join(dir, "Act.img.xml"), `name="npcAct"`, `name="unknownAct"`)
+	err = c.Reload()
+	if err != nil {
+		t.Fatalf("reloading data with new diagnostics failed: %v", err)
+	}
+	if len(c.GetDiagnostics()) == 0 || len(c.GetQuests()) != quests {
+		t.Errorf("reload with new diagnostics did not replace the data in use")
+	}
+	diagnostics := len(c.GetDiagnostics())
+
+	rewrite(t, filepath.Join(dir, "QuestInfo.img.xml"), `<imgdir name="4513">`, `<imgdir name="lost">`)
+	err = c.Reload()
+	var re RegressionError
+	if !errors.As(err, &re) || len(re.Missing) != 1 || re.Missing[0] != 4513 {
+		t.Fatalf("reloading data without quest 4513 returned %v, want a regression missing it", err)
+	}
+	if len(c.GetDiagnostics()) != diagnostics || len(c.GetQuests()) != quests {
+		t.Errorf("refused reload replaced the data in use")
+	}
+
+	err = c.ForceReload()
+	if err != nil {
+		t.Fatalf("forced reload failed: %v", err)
+	}
+	if len(c.GetQuests()) != quests-1 {
+		t.Errorf("forced reload did not replace the data in use")
+	}
+}
+
+func TestReloadInitsDependentCaches(t *testing.T) {
+	dir := copyQuestData(t)
+	tm := tenant.NewModel("dependent-test", dir, "", "")
+	wz.GetFileCache(tm.Id()).Init(dir)
+	err := GetCache(tm).Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloads := 0
+	fail := false
+	RegisterDependentCache("test", func(t tenant.Model) error {
+		if t.Id() != tm.Id() {
+			return nil
+		}
+		reloads++
+		if fail {
+			return errors.New("dependent failed")
+		}
+		return nil
+	})
+
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	err = Reload(l, tm, false)
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if reloads != 1 {
+		t.Errorf("dependent cache reloaded %d times, want 1", reloads)
+	}
+
+	fail = true
+	err = Reload(l, tm, false)
+	if err == nil {
+		t.Errorf("Reload() error = nil, want the dependent cache failure")
+	}
+	if reloads != 2 {
+		t.Errorf("dependent cache reloaded %d times, want 2", reloads)
+	}
+}
+
+func TestGetExclusiveItems(t *testing.T) {
+	tm := loadQuests(t)
+
+	for _, tc := range []struct {
+		name    string
+		questId uint16
+		itemId  uint32
+		want    bool
+	}{
+		{"consumed on completion", 2145, 4031773, true},
+		{"given on start", 3302, 4031705, true},
+		{"granted on completion", 1040, 4031801, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := false
+			for _, it := range GetCache(tm).GetExclusiveItems(tc.questId) {
+				if it == tc.itemId {
+					got = true
+				}
+			}
+			if got != tc.want {
+				t.Errorf("GetExclusiveItems(%d) holds item %d = %t, want %t", tc.questId, tc.itemId, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package conversation
+
+import (
+	"errors"
+	"gorm.io/gorm"
+)
+
+// record stores the outcome of the character's latest attempt at the conversation.
+func record(db *gorm.DB, characterId uint32, questId uint16, phase string, passed bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var e entity
+		err := tx.Where(&entity{CharacterId: characterId, QuestId: questId, Phase: phase}).First(&e).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			e = entity{CharacterId: characterId, QuestId: questId, Phase: phase}
+		} else if err != nil {
+			return err
+		}
+		e.Passed = passed
+		return tx.Save(&e).Error
+	})
+}
+
+// clearQuest removes the outcomes of the character's attempts at the conversations of the quest.
+func clearQuest(db *gorm.DB, characterId uint32, questId uint16) error {
+	return db.Where(&entity{CharacterId: characterId, QuestId: questId}).Delete(&entity{}).Error
+}
+
+func deleteAll(db *gorm.DB, characterId uint32) error {
+	return db.Where(&entity{CharacterId: characterId}).Delete(&entity{}).Error
+}
+
+// restore replaces the character's conversation outcomes with those given.
+func restore(db *gorm.DB, characterId uint32, outcomes []Outcome) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := deleteAll(tx, characterId)
+		if err != nil {
+			return err
+		}
+		for _, o := range outcomes {
+			err = tx.Create(&entity{CharacterId: characterId, QuestId: o.QuestId(), Phase: o.Phase(), Passed: o.Passed()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,23 @@
+package conversation
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+type entity struct {
+	ID          uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId uint32 `gorm:"not null;uniqueIndex:idx_quest_conversation_character"`
+	QuestId     uint16 `gorm:"not null;uniqueIndex:idx_quest_conversation_character"`
+	Phase       string `gorm:"not null;uniqueIndex:idx_quest_conversation_character"`
+	Passed      bool   `gorm:"not null;default:false"`
+	UpdatedAt   time.Time
+}
+
+func (e entity) TableName() string {
+	return "quest_conversations"
+}
@@ -0,0 +1,154 @@
+package conversation
+
+const (
+	PhaseStart    = "start"
+	PhaseComplete = "complete"
+)
+
+// Model is the scripted NPC dialogue for a quest phase. Steps which offer a selection menu are questions, and may
+// carry an expected answer. When ask is set, the conversation closes with a yes / no confirmation.
+type Model struct {
+	steps []Step
+	ask   bool
+	yes   []string
+	no    []string
+}
+
+func (m Model) Steps() []Step {
+	return m.steps
+}
+
+func (m Model) Ask() bool {
+	return m.ask
+}
+
+func (m Model) Yes() []string {
+	return m.yes
+}
+
+func (m Model) No() []string {
+	return m.no
+}
+
+func (m Model) Empty() bool {
+	return len(m.steps) == 0 && !m.ask && len(m.yes) == 0 && len(m.no) == 0
+}
+
+// Questions returns the steps which require a selection, in the order they are presented.
+func (m Model) Questions() []Step {
+	results := make([]Step, 0)
+	for _, s := range m.steps {
+		if s.Question() {
+			results = append(results, s)
+		}
+	}
+	return results
+}
+
+// Gated reports whether the conversation must be passed before the quest phase may proceed.
+func (m Model) Gated() bool {
+	if m.ask {
+		return true
+	}
+	for _, s := range m.steps {
+		if s.answer > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate validates the selections made in answer to each question, and the response to the closing confirmation.
+// Selections are zero based, matching the menu options offered in the dialogue.
+func (m Model) Evaluate(selections []uint32, accepted bool) Result {
+	i := 0
+	for _, s := range m.steps {
+		if !s.Question() {
+			continue
+		}
+		if i >= len(selections) {
+			return Result{step: s.index}
+		}
+		if s.answer > 0 && selections[i] != s.answer-1 {
+			return Result{step: s.index, dialogue: s.stop}
+		}
+		i++
+	}
+	if m.ask && !accepted {
+		return Result{step: -1, dialogue: m.no}
+	}
+	return Result{passed: true, step: -1, dialogue: m.yes}
+}
+
+type Step struct {
+	index    int
+	text     string
+	question bool
+	answer   uint32
+	stop     []string
+}
+
+func (s Step) Index() int {
+	return s.index
+}
+
+func (s Step) Text() string {
+	return s.text
+}
+
+func (s Step) Question() bool {
+	return s.question
+}
+
+// Answer is the one based menu option expected in response to the step, or zero when any selection is acceptable.
+func (s Step) Answer() uint32 {
+	return s.answer
+}
+
+// Stop is the dialogue shown when the expected answer is not given.
+func (s Step) Stop() []string {
+	return s.stop
+}
+
+// Result is the outcome of evaluating a set of answers. When not passed, step identifies the question which was
+// answered incorrectly or left unanswered, or is -1 when the closing confirmation was declined.
+type Result struct {
+	passed   bool
+	step     int
+	dialogue []string
+}
+
+func (r Result) Passed() bool {
+	return r.passed
+}
+
+func (r Result) Step() int {
+	return r.step
+}
+
+func (r Result) Dialogue() []string {
+	return r.dialogue
+}
+
+// Outcome is whether the character passed their latest attempt at the conversation of a quest phase.
+type Outcome struct {
+	questId uint16
+	phase   string
+	passed  bool
+}
+
+func NewOutcome(questId uint16, phase string, passed bool) Outcome {
+	return Outcome{questId: questId, phase: phase, passed: passed}
+}
+
+func (o Outcome) QuestId() uint16 {
+	return o.questId
+}
+
+func (o Outcome) Phase() string {
+	return o.phase
+}
+
+func (o Outcome) Passed() bool {
+	return o.passed
+}
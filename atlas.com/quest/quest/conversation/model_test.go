@@ -0,0 +1,87 @@
+package conversation
+
+import (
+	"atlas-quest/xml"
+	"os"
+	"strings"
+	"testing"
+)
+
+const actPath = "../../../../wz/Quest.wz/Act.img.xml"
+
+func startingConversation(t *testing.T, questId string) Model {
+	if _, err := os.Stat(actPath); err != nil {
+		t.Skipf("%s is not available.", actPath)
+	}
+	root, err := xml.Read(actPath, xml.Only(questId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	qn, err := root.ChildByName(questId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := GetStarting(qn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestEvaluate(t *testing.T) {
+	// 10023 asks a single question answered by the first option, then asks for confirmation. 2122 asks two questions,
+	// each answered by the first option, then asks for confirmation.
+	for _, tc := range []struct {
+		name       string
+		questId    string
+		selections []uint32
+		accepted   bool
+		passed     bool
+		step       int
+		dialogue   string
+	}{
+		{"correct and accepted", "10023", []uint32{0}, true, true, -1, "yes"},
+		{"correct and declined", "10023", []uint32{0}, false, false, -1, "no"},
+		{"incorrect", "10023", []uint32{1}, true, false, 0, "stop"},
+		{"unanswered", "10023", nil, true, false, 0, ""},
+		{"both correct", "2122", []uint32{0, 0}, true, true, -1, "yes"},
+		{"second incorrect", "2122", []uint32{0, 1}, true, false, 1, "stop"},
+		{"second unanswered", "2122", []uint32{0}, true, false, 1, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := startingConversation(t, tc.questId)
+			if !m.Gated() {
+				t.Fatalf("conversation of quest %s is not gated", tc.questId)
+			}
+			r := m.Evaluate(tc.selections, tc.accepted)
+			if r.Passed() != tc.passed || r.Step() != tc.step {
+				t.Fatalf("Evaluate(%v, %t) = passed %t step %d, want passed %t step %d", tc.selections, tc.accepted, r.Passed(), r.Step(), tc.passed, tc.step)
+			}
+
+			var want []string
+			switch tc.dialogue {
+			case "yes":
+				want = m.Yes()
+			case "no":
+				want = m.No()
+			case "stop":
+				want = m.Steps()[tc.step].Stop()
+			}
+			if strings.Join(r.Dialogue(), "\n") != strings.Join(want, "\n") {
+				t.Errorf("Evaluate(%v, %t) dialogue = %v, want %s dialogue %v", tc.selections, tc.accepted, r.Dialogue(), tc.dialogue, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateAnyAnswer(t *testing.T) {
+	m := Model{steps: []Step{{index: 0, text: "#L0#a#l #L1#b#l", question: true}}}
+	if m.Gated() {
+		t.Fatalf("conversation without expected answers or confirmation is gated")
+	}
+	for _, selection := range []uint32{0, 1} {
+		if r := m.Evaluate([]uint32{selection}, false); !r.Passed() {
+			t.Errorf("Evaluate([%d], false) = step %d, want passed", selection, r.Step())
+		}
+	}
+}
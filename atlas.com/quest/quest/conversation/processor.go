@@ -0,0 +1,84 @@
+package conversation
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/xml"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func GetStarting(root xml.Noder) (Model, error) {
+	return get(root, "0")
+}
+
+func GetEnding(root xml.Noder) (Model, error) {
+	return get(root, "1")
+}
+
+// Submit evaluates the character's answers to the conversation, and records whether it was passed.
+func Submit(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16, phase string, m Model, selections []uint32, accepted bool) (Result, error) {
+	return func(characterId uint32, questId uint16, phase string, m Model, selections []uint32, accepted bool) (Result, error) {
+		r := m.Evaluate(selections, accepted)
+		err := record(db, characterId, questId, phase, r.Passed())
+		if err != nil {
+			l.WithError(err).Errorf("Unable to record %s conversation outcome of quest %d for character %d.", phase, questId, characterId)
+			return r, err
+		}
+		return r, nil
+	}
+}
+
+// Passed reports whether the character's latest attempt at the conversation was successful.
+func Passed(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16, phase string) (bool, error) {
+	return func(characterId uint32, questId uint16, phase string) (bool, error) {
+		e, err := byCharacterQuestAndPhaseEntityProvider(characterId, questId, phase)(db)()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return e.Passed, nil
+	}
+}
+
+// GetOutcomes retrieves the outcome of every conversation the character has attempted.
+func GetOutcomes(_ logrus.FieldLogger, db *gorm.DB) func(characterId uint32) ([]Outcome, error) {
+	return func(characterId uint32) ([]Outcome, error) {
+		return database.ModelSliceProvider[Outcome, entity](db)(byCharacterEntityProvider(characterId), makeOutcome)()
+	}
+}
+
+// Clear removes the outcome of the character's attempts at the conversations of the quest, so they must be passed again.
+func Clear(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, questId uint16) error {
+	return func(characterId uint32, questId uint16) error {
+		err := clearQuest(db, characterId, questId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to clear quest %d conversation outcomes of character %d.", questId, characterId)
+		}
+		return err
+	}
+}
+
+// Delete removes the outcome of every conversation the character has attempted.
+func Delete(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32) error {
+	return func(characterId uint32) error {
+		err := deleteAll(db, characterId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to delete conversation outcomes of character %d.", characterId)
+		}
+		return err
+	}
+}
+
+// Restore replaces the conversation outcomes held for the character with those given.
+func Restore(l logrus.FieldLogger, db *gorm.DB) func(characterId uint32, outcomes []Outcome) error {
+	return func(characterId uint32, outcomes []Outcome) error {
+		err := restore(db, characterId, outcomes)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to restore conversation outcomes of character %d.", characterId)
+		}
+		return err
+	}
+}
@@ -0,0 +1,23 @@
+package conversation
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/model"
+	"gorm.io/gorm"
+)
+
+func byCharacterQuestAndPhaseEntityProvider(characterId uint32, questId uint16, phase string) database.EntityProvider[entity] {
+	return func(db *gorm.DB) model.Provider[entity] {
+		return database.Query[entity](db, &entity{CharacterId: characterId, QuestId: questId, Phase: phase})
+	}
+}
+
+func byCharacterEntityProvider(characterId uint32) database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{CharacterId: characterId})
+	}
+}
+
+func makeOutcome(e entity) (Outcome, error) {
+	return Outcome{questId: e.QuestId, phase: e.Phase, passed: e.Passed}, nil
+}
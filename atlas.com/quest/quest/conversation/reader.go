@@ -0,0 +1,120 @@
+package conversation
+
+import (
+	"atlas-quest/xml"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IsNode reports whether the Act node named forms part of the phase conversation, rather than being an action.
+func IsNode(name string) bool {
+	switch name {
+	case "ask", "stop", "yes", "no":
+		return true
+	}
+	_, err := strconv.Atoi(name)
+	return err == nil
+}
+
+func get(root xml.Noder, nodeName string) (Model, error) {
+	questData, ok := root.(xml.Parent)
+	if !ok {
+		return Model{}, errors.New("invalid xml structure")
+	}
+
+	phaseRoot, err := questData.ChildByName(nodeName)
+	if err != nil {
+		return Model{}, nil
+	}
+	rootAsParent, ok := phaseRoot.(xml.Parent)
+	if !ok {
+		return Model{}, errors.New("invalid xml structure")
+	}
+
+	m := Model{steps: make([]Step, 0)}
+	for i, text := range readLines(rootAsParent) {
+		m.steps = append(m.steps, Step{index: i, text: text, question: strings.Contains(text, "#L")})
+	}
+
+	ask, err := xml.GetBoolean(rootAsParent, "ask")
+	if err == nil {
+		m.ask = ask
+	}
+	if yn, err := rootAsParent.ChildByName("yes"); err == nil {
+		if yp, ok := yn.(xml.Parent); ok {
+			m.yes = readLines(yp)
+		}
+	}
+	if nn, err := rootAsParent.ChildByName("no"); err == nil {
+		if np, ok := nn.(xml.Parent); ok {
+			m.no = readLines(np)
+		}
+	}
+
+	sn, err := rootAsParent.ChildByName("stop")
+	if err != nil {
+		return m, nil
+	}
+	sp, ok := sn.(xml.Parent)
+	if !ok {
+		return m, errors.New("invalid xml structure")
+	}
+	// stop entries are numbered by question, not by step.
+	questions := make([]int, 0)
+	for i, s := range m.steps {
+		if s.question {
+			questions = append(questions, i)
+		}
+	}
+	for _, c := range sp.Children() {
+		n, err := strconv.Atoi(c.Name())
+		if err != nil {
+			continue
+		}
+		if n >= len(questions) {
+			return m, errors.New("stop entry without matching question")
+		}
+		cp, ok := c.(xml.Parent)
+		if !ok {
+			return m, errors.New("invalid xml structure")
+		}
+		s := &m.steps[questions[n]]
+		answer, err := xml.GetInteger(cp, "answer")
+		if err == nil {
+			s.answer = uint32(answer)
+		}
+		s.stop = readLines(cp)
+	}
+	return m, nil
+}
+
+// readLines collects the numbered string children of p, in numeric order.
+func readLines(p xml.Parent) []string {
+	type line struct {
+		index int
+		value string
+	}
+	ls := make([]line, 0)
+	for _, c := range p.Children() {
+		sn, ok := c.(*xml.StringNode)
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(sn.Name())
+		if err != nil {
+			continue
+		}
+		ls = append(ls, line{index: i, value: sn.Value()})
+	}
+	sort.Slice(ls, func(i, j int) bool {
+		return ls[i].index < ls[j].index
+	})
+
+	results := make([]string, 0, len(ls))
+	for _, l := range ls {
+		results = append(results, l.value)
+	}
+	return results
+}
@@ -0,0 +1,68 @@
+package diagnostic
+
+import "fmt"
+
+const (
+	PhaseInfo                = "INFO"
+	PhaseStartRequirement    = "START_REQUIREMENT"
+	PhaseCompleteRequirement = "COMPLETE_REQUIREMENT"
+	PhaseStartAction         = "START_ACTION"
+	PhaseCompleteAction      = "COMPLETE_ACTION"
+)
+
+type Phase string
+
+// Model describes a problem encountered while loading a single quest. The quest is either skipped or partially loaded,
+// depending on where the problem occurred.
+type Model struct {
+	questId uint16
+	phase   Phase
+	path    string
+	err     error
+	skipped bool
+}
+
+// NewModel describes a problem which left the quest partially loaded, without the offending part.
+func NewModel(questId uint16, phase Phase, path string, err error) Model {
+	return Model{
+		questId: questId,
+		phase:   phase,
+		path:    path,
+		err:     err,
+	}
+}
+
+// NewSkipped describes a problem which prevented the quest from being loaded at all.
+func NewSkipped(questId uint16, phase Phase, path string, err error) Model {
+	m := NewModel(questId, phase, path, err)
+	m.skipped = true
+	return m
+}
+
+func (m Model) QuestId() uint16 {
+	return m.questId
+}
+
+func (m Model) Phase() Phase {
+	return m.phase
+}
+
+func (m Model) Path() string {
+	return m.path
+}
+
+func (m Model) Err() error {
+	return m.err
+}
+
+// Skipped reports whether the quest was left out entirely, rather than loaded without the offending part.
+func (m Model) Skipped() bool {
+	return m.skipped
+}
+
+func (m Model) Error() string {
+	if m.skipped {
+		return fmt.Sprintf("quest %d failed to load %s at %s: %s", m.questId, m.phase, m.path, m.err)
+	}
+	return fmt.Sprintf("quest %d partially loaded, ignoring %s at %s: %s", m.questId, m.phase, m.path, m.err)
+}
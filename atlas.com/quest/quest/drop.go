@@ -0,0 +1,67 @@
+package quest
+
+import (
+	"atlas-quest/character/inventory"
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GetDropEligibility narrows the candidate items to those the character may receive as a quest drop. An item is
+// eligible when a quest the character has started requires it for completion, and the character holds fewer than that
+// quest requires. Candidates no quest requires are never eligible. The character's quests and inventory are only
+// consulted when some candidate is a quest item.
+func GetDropEligibility(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, itemIds []uint32) ([]uint32, error) {
+	return func(characterId uint32, itemIds []uint32) ([]uint32, error) {
+		results := make([]uint32, 0)
+		counts := make(map[uint32]map[uint16]int32)
+		for _, id := range itemIds {
+			if _, ok := counts[id]; ok {
+				continue
+			}
+			if qs := GetCache(t).GetCompletionCounts(id); len(qs) > 0 {
+				counts[id] = qs
+			}
+		}
+		if len(counts) == 0 {
+			return results, nil
+		}
+
+		started, err := characterquest.QuestsByStatus(l, span, db)(characterId, characterquest.StatusStarted)
+		if err != nil {
+			return nil, err
+		}
+		needed := make(map[uint32]int32)
+		for _, s := range started {
+			for id, qs := range counts {
+				if c, ok := qs[s.Id()]; ok && c > needed[id] {
+					needed[id] = c
+				}
+			}
+		}
+		if len(needed) == 0 {
+			return results, nil
+		}
+
+		ids := make([]uint32, 0, len(needed))
+		for id := range needed {
+			ids = append(ids, id)
+		}
+		held, err := inventory.GetQuantities(l, span, t)(characterId, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range itemIds {
+			c, ok := needed[id]
+			if !ok || int64(held[id]) >= int64(c) {
+				continue
+			}
+			results = append(results, id)
+			delete(needed, id)
+		}
+		return results, nil
+	}
+}
@@ -0,0 +1,30 @@
+package event
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+func upsert(db *gorm.DB, questId uint16, start time.Time, end time.Time) (Window, error) {
+	var result entity
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(&entity{QuestId: questId}).First(&result).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result = entity{QuestId: questId}
+		} else if err != nil {
+			return err
+		}
+		result.Start = timeOrNil(start)
+		result.End = timeOrNil(end)
+		return tx.Save(&result).Error
+	})
+	if err != nil {
+		return Window{}, err
+	}
+	return makeWindow(result)
+}
+
+func remove(db *gorm.DB, questId uint16) error {
+	return db.Where(&entity{QuestId: questId}).Delete(&entity{}).Error
+}
@@ -0,0 +1,41 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DateLayout is the YYYYMMDDHH form used by the quest data for event start and end dates.
+const DateLayout = "2006010215"
+
+var location = time.Local
+var locationLock sync.RWMutex
+
+// SetLocation sets the server timezone event dates are read in. This should be done prior to loading the quest data.
+func SetLocation(loc *time.Location) {
+	locationLock.Lock()
+	defer locationLock.Unlock()
+	location = loc
+}
+
+func Location() *time.Location {
+	locationLock.RLock()
+	defer locationLock.RUnlock()
+	return location
+}
+
+func ParseDate(val string) (time.Time, error) {
+	if len(val) != len(DateLayout) {
+		return time.Time{}, errors.New(fmt.Sprintf("date [%s] is not in YYYYMMDDHH form", val))
+	}
+	return time.ParseInLocation(DateLayout, val, Location())
+}
+
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(Location()).Format(DateLayout)
+}
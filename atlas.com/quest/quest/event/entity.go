@@ -0,0 +1,21 @@
+package event
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+func Migration(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+type entity struct {
+	ID      uint32     `gorm:"primaryKey;autoIncrement;not null"`
+	QuestId uint16     `gorm:"not null;uniqueIndex"`
+	Start   *time.Time `gorm:""`
+	End     *time.Time `gorm:""`
+}
+
+func (e entity) TableName() string {
+	return "quest_event_windows"
+}
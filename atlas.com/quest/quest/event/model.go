@@ -0,0 +1,64 @@
+package event
+
+import "time"
+
+const (
+	StatusActive   = "ACTIVE"
+	StatusUpcoming = "UPCOMING"
+	StatusEnded    = "ENDED"
+)
+
+// Window is the period in which a seasonal quest may be started. A zero start or end leaves the window open on that
+// side.
+type Window struct {
+	questId    uint16
+	start      time.Time
+	end        time.Time
+	overridden bool
+}
+
+func NewWindow(questId uint16, start time.Time, end time.Time) Window {
+	return Window{questId: questId, start: start, end: end}
+}
+
+func (w Window) QuestId() uint16 {
+	return w.questId
+}
+
+func (w Window) Start() time.Time {
+	return w.start
+}
+
+func (w Window) End() time.Time {
+	return w.end
+}
+
+// Overridden reports whether the window was set by an operator, rather than read from the quest data.
+func (w Window) Overridden() bool {
+	return w.overridden
+}
+
+// Bounded reports whether the window restricts when the quest may be started at all.
+func (w Window) Bounded() bool {
+	return !w.start.IsZero() || !w.end.IsZero()
+}
+
+func (w Window) Open(t time.Time) bool {
+	if !w.start.IsZero() && t.Before(w.start) {
+		return false
+	}
+	if !w.end.IsZero() && !t.Before(w.end) {
+		return false
+	}
+	return true
+}
+
+func (w Window) Status(t time.Time) string {
+	if !w.start.IsZero() && t.Before(w.start) {
+		return StatusUpcoming
+	}
+	if !w.end.IsZero() && !t.Before(w.end) {
+		return StatusEnded
+	}
+	return StatusActive
+}
@@ -0,0 +1,79 @@
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	defer SetLocation(Location())
+	kst := time.FixedZone("KST", 9*60*60)
+	SetLocation(kst)
+
+	for _, tc := range []struct {
+		val  string
+		want time.Time
+		ok   bool
+	}{
+		{"2008122300", time.Date(2008, 12, 23, 0, 0, 0, 0, kst), true},
+		{"2009010523", time.Date(2009, 1, 5, 23, 0, 0, 0, kst), true},
+		{"20081223", time.Time{}, false},
+		{"200812230000", time.Time{}, false},
+		{"2008122324", time.Time{}, false},
+		{"2008132300", time.Time{}, false},
+		{"abcdefghij", time.Time{}, false},
+	} {
+		got, err := ParseDate(tc.val)
+		if (err == nil) != tc.ok {
+			t.Errorf("ParseDate(%s) error = %v, want ok %t", tc.val, err, tc.ok)
+			continue
+		}
+		if tc.ok && !got.Equal(tc.want) {
+			t.Errorf("ParseDate(%s) = %s, want %s", tc.val, got, tc.want)
+		}
+		if tc.ok && FormatDate(got) != tc.val {
+			t.Errorf("FormatDate(ParseDate(%s)) = %s", tc.val, FormatDate(got))
+		}
+	}
+
+	// the same date read in another timezone is a different instant.
+	SetLocation(time.UTC)
+	utc, err := ParseDate("2008122300")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := utc.Sub(time.Date(2008, 12, 23, 0, 0, 0, 0, kst)); d != 9*time.Hour {
+		t.Errorf("UTC date is %s after the KST date, want 9h", d)
+	}
+}
+
+func TestWindowStatus(t *testing.T) {
+	start := time.Date(2008, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2009, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name   string
+		window Window
+		at     time.Time
+		want   string
+	}{
+		{"before start", NewWindow(1, start, end), start.Add(-time.Second), StatusUpcoming},
+		{"at start", NewWindow(1, start, end), start, StatusActive},
+		{"before end", NewWindow(1, start, end), end.Add(-time.Second), StatusActive},
+		{"at end", NewWindow(1, start, end), end, StatusEnded},
+		{"open start", NewWindow(1, time.Time{}, end), start.AddDate(-10, 0, 0), StatusActive},
+		{"open start at end", NewWindow(1, time.Time{}, end), end, StatusEnded},
+		{"open end", NewWindow(1, start, time.Time{}), end.AddDate(10, 0, 0), StatusActive},
+		{"unbounded", NewWindow(1, time.Time{}, time.Time{}), start, StatusActive},
+	} {
+		if got := tc.window.Status(tc.at); got != tc.want {
+			t.Errorf("%s: Status() = %s, want %s", tc.name, got, tc.want)
+		}
+		if got := tc.window.Open(tc.at); got != (tc.want == StatusActive) {
+			t.Errorf("%s: Open() = %t, want %t", tc.name, got, tc.want == StatusActive)
+		}
+	}
+	if NewWindow(1, time.Time{}, time.Time{}).Bounded() {
+		t.Errorf("window without start or end is bounded")
+	}
+}
@@ -0,0 +1,38 @@
+package event
+
+import (
+	"atlas-quest/tenant"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Override replaces the window of the quest. A zero start or end leaves the window open on that side.
+func Override(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(questId uint16, start time.Time, end time.Time) (Window, error) {
+	return func(questId uint16, start time.Time, end time.Time) (Window, error) {
+		if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+			return Window{}, errors.New("event window must start before it ends")
+		}
+		w, err := upsert(db, questId, start, end)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to override event window of quest %d.", questId)
+			return Window{}, err
+		}
+		GetRegistry().set(t.Id(), w)
+		return w, nil
+	}
+}
+
+// ClearOverride restores the window of the quest to that of the quest data.
+func ClearOverride(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(questId uint16) error {
+	return func(questId uint16) error {
+		err := remove(db, questId)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to clear event window override of quest %d.", questId)
+			return err
+		}
+		GetRegistry().clear(t.Id(), questId)
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package event
+
+import (
+	"atlas-quest/database"
+	"atlas-quest/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+func allEntityProvider() database.EntitySliceProvider[entity] {
+	return func(db *gorm.DB) model.SliceProvider[entity] {
+		return database.SliceQuery[entity](db, &entity{})
+	}
+}
+
+func makeWindow(e entity) (Window, error) {
+	w := Window{questId: e.QuestId, overridden: true}
+	if e.Start != nil {
+		w.start = *e.Start
+	}
+	if e.End != nil {
+		w.end = *e.End
+	}
+	return w, nil
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
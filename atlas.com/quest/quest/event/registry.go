@@ -0,0 +1,69 @@
+package event
+
+import (
+	"atlas-quest/database"
+	"gorm.io/gorm"
+	"sync"
+)
+
+// registry holds the operator overrides in memory, so quest checks need not consult the database.
+type registry struct {
+	overrides map[string]map[uint16]Window
+	lock      sync.RWMutex
+}
+
+var once sync.Once
+var r *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		r = &registry{
+			overrides: make(map[string]map[uint16]Window),
+			lock:      sync.RWMutex{},
+		}
+	})
+	return r
+}
+
+// Init loads the persisted overrides of the tenant, replacing those currently held.
+func (r *registry) Init(tenantId string, db *gorm.DB) error {
+	ws, err := database.ModelSliceProvider[Window, entity](db)(allEntityProvider(), makeWindow)()
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[uint16]Window)
+	for _, w := range ws {
+		overrides[w.QuestId()] = w
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.overrides[tenantId] = overrides
+	return nil
+}
+
+func (r *registry) set(tenantId string, w Window) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.overrides[tenantId]; !ok {
+		r.overrides[tenantId] = make(map[uint16]Window)
+	}
+	r.overrides[tenantId][w.QuestId()] = w
+}
+
+func (r *registry) clear(tenantId string, questId uint16) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.overrides[tenantId], questId)
+}
+
+// Resolve returns the override of the quest window, should an operator have set one.
+func (r *registry) Resolve(tenantId string, base Window) Window {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if o, ok := r.overrides[tenantId][base.QuestId()]; ok {
+		return o
+	}
+	return base
+}
@@ -0,0 +1,119 @@
+package quest
+
+import (
+	"atlas-quest/character/inventory"
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/forfeit"
+	"atlas-quest/tenant"
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrNotForfeitable = errors.New("quest cannot be forfeited")
+	ErrNotStarted     = errors.New("quest has not been started")
+)
+
+// Forfeiture is the outcome of a character forfeiting a quest.
+type Forfeiture struct {
+	questId     uint16
+	forfeits    uint32
+	availableAt time.Time
+	removed     map[uint32]uint32
+}
+
+func (f Forfeiture) QuestId() uint16 {
+	return f.questId
+}
+
+// Forfeits is the number of times the character has now forfeited the quest.
+func (f Forfeiture) Forfeits() uint32 {
+	return f.forfeits
+}
+
+// AvailableAt is when the quest may next be started.
+func (f Forfeiture) AvailableAt() time.Time {
+	return f.availableAt
+}
+
+// Removed is the quantity of each quest item taken from the character, keyed by item id.
+func (f Forfeiture) Removed() map[uint32]uint32 {
+	return f.removed
+}
+
+// CanForfeit reports whether the quest may be forfeited. Medal quests and job advancement quests may not be, nor may
+// those the forfeit rules block.
+func CanForfeit(t tenant.Model, q Model) bool {
+	return q.MedalId() == 0 && !q.JobAdvancement() && !forfeit.GetRegistry().IsBlocked(t.Id(), q.Id())
+}
+
+// availableAt is when the quest may next be started by the character, given the forfeit cooldown.
+func availableAt(t tenant.Model, cq characterquest.Model) time.Time {
+	if cq.Forfeited().IsZero() {
+		return time.Time{}
+	}
+	return cq.Forfeited().Add(forfeit.GetRegistry().Cooldown(t.Id(), cq.Id()))
+}
+
+// Forfeit abandons the quest the character has in progress. The kill progress, info and conversation outcomes held for
+// the quest are discarded. Once the forfeit is recorded, the quest items no other quest makes use of are taken from the
+// character. Items which cannot be taken are left with the character, and are absent from the removed items reported.
+func Forfeit(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint32) (Forfeiture, error) {
+	return func(characterId uint32, questId uint32) (Forfeiture, error) {
+		q, err := GetById(l, t)(questId)
+		if err != nil {
+			return Forfeiture{}, err
+		}
+		if !CanForfeit(t, q) {
+			return Forfeiture{}, ErrNotForfeitable
+		}
+
+		cq, err := characterquest.GetById(l, span, db)(characterId, q.Id())
+		if err != nil {
+			return Forfeiture{}, err
+		}
+		if cq.Status() != characterquest.StatusStarted {
+			return Forfeiture{}, ErrNotStarted
+		}
+
+		held := make(map[uint32]uint32)
+		if ids := GetCache(t).GetExclusiveItems(q.Id()); len(ids) > 0 {
+			held, err = inventory.GetQuantities(l, span, t)(characterId, ids)
+			if err != nil {
+				return Forfeiture{}, err
+			}
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			cq, err = characterquest.Forfeit(l, tx)(characterId, q.Id())
+			if err != nil {
+				return err
+			}
+			return conversation.Clear(l, tx)(characterId, q.Id())
+		})
+		if errors.Is(err, characterquest.ErrStatusChanged) {
+			return Forfeiture{}, ErrNotStarted
+		}
+		if err != nil {
+			return Forfeiture{}, err
+		}
+
+		removed := make(map[uint32]uint32)
+		for id, quantity := range held {
+			if quantity == 0 {
+				continue
+			}
+			err = inventory.RemoveItems(l, span, t)(characterId, map[uint32]uint32{id: quantity})
+			if err != nil {
+				l.WithError(err).Errorf("Unable to remove item %d of forfeited quest %d from character %d.", id, q.Id(), characterId)
+				continue
+			}
+			removed[id] = quantity
+		}
+		return Forfeiture{questId: q.Id(), forfeits: cq.Forfeits(), availableAt: availableAt(t, cq), removed: removed}, nil
+	}
+}
@@ -0,0 +1,60 @@
+package forfeit
+
+import (
+	"atlas-quest/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// configuration is the on disk layout of the forfeit rules file. Durations are in the form accepted by
+// time.ParseDuration. Quests without a cooldown of their own use the default.
+//
+//	{
+//	  "blocked": [1405, 1406],
+//	  "cooldown": "10m",
+//	  "cooldowns": {"2049": "24h"}
+//	}
+type configuration struct {
+	Blocked   []uint16          `json:"blocked"`
+	Cooldown  string            `json:"cooldown"`
+	Cooldowns map[uint16]string `json:"cooldowns"`
+}
+
+func read(path string) (map[uint16]bool, time.Duration, map[uint16]time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer f.Close()
+
+	c := &configuration{}
+	err = json.FromJSON(c, f)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	blocked := make(map[uint16]bool)
+	for _, id := range c.Blocked {
+		blocked[id] = true
+	}
+
+	var cooldown time.Duration
+	if c.Cooldown != "" {
+		cooldown, err = time.ParseDuration(c.Cooldown)
+		if err != nil {
+			return nil, 0, nil, errors.New(fmt.Sprintf("invalid cooldown [%s]", c.Cooldown))
+		}
+	}
+
+	cooldowns := make(map[uint16]time.Duration)
+	for id, val := range c.Cooldowns {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, 0, nil, errors.New(fmt.Sprintf("invalid cooldown [%s] for quest %d", val, id))
+		}
+		cooldowns[id] = d
+	}
+	return blocked, cooldown, cooldowns, nil
+}
@@ -0,0 +1,63 @@
+package forfeit
+
+import (
+	"sync"
+	"time"
+)
+
+// rules is the forfeit configuration of a single tenant.
+type rules struct {
+	blocked   map[uint16]bool
+	cooldown  time.Duration
+	cooldowns map[uint16]time.Duration
+}
+
+type registry struct {
+	tenants map[string]rules
+	lock    sync.RWMutex
+}
+
+var once sync.Once
+var r *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		r = &registry{
+			tenants: make(map[string]rules),
+			lock:    sync.RWMutex{},
+		}
+	})
+	return r
+}
+
+// Init loads the forfeit rules file at path for the tenant, replacing any previously loaded rules. Without rules any
+// quest may be forfeited and restarted immediately.
+func (r *registry) Init(tenantId string, path string) error {
+	blocked, cooldown, cooldowns, err := read(path)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tenants[tenantId] = rules{blocked: blocked, cooldown: cooldown, cooldowns: cooldowns}
+	return nil
+}
+
+// IsBlocked reports whether the rules forbid the quest from being forfeited.
+func (r *registry) IsBlocked(tenantId string, questId uint16) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.tenants[tenantId].blocked[questId]
+}
+
+// Cooldown is how long after being forfeited the quest may be started again.
+func (r *registry) Cooldown(tenantId string, questId uint16) time.Duration {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	rs := r.tenants[tenantId]
+	if d, ok := rs.cooldowns[questId]; ok {
+		return d
+	}
+	return rs.cooldown
+}
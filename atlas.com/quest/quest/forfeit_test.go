@@ -0,0 +1,121 @@
+package quest
+
+import (
+	"atlas-quest/character/inventory"
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestForfeit(t *testing.T) {
+	tm := loadQuests(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(forfeitQuest)
+
+	const (
+		characterId = uint32(1)
+		questId     = uint16(2145)
+		otherId     = uint16(2146)
+		itemId      = uint32(4031773)
+	)
+	for _, tc := range []struct {
+		name        string
+		failRemoval bool
+		removed     uint32
+		held        uint32
+	}{
+		{"items removed", false, 2, 0},
+		{"items kept", true, 0, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := openDatabase(t)
+			inv := &fakeInventory{items: map[uint32]uint32{itemId: 2}, failRemoval: tc.failRemoval}
+			defer inventory.SetClient(inventory.GetClient())
+			inventory.SetClient(inv)
+
+			if _, err := characterquest.Start(l, db)(characterId, questId, characterquest.StatusNotStarted); err != nil {
+				t.Fatal(err)
+			}
+			outcomes := []conversation.Outcome{
+				conversation.NewOutcome(questId, conversation.PhaseStart, true),
+				conversation.NewOutcome(otherId, conversation.PhaseStart, true),
+			}
+			if err := conversation.Restore(l, db)(characterId, outcomes); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := Forfeit(l, span, db, tm)(characterId, uint32(questId))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Forfeits() != 1 || f.Removed()[itemId] != tc.removed || inv.items[itemId] != tc.held {
+				t.Fatalf("forfeit = %d forfeits, %d removed, %d held, want 1, %d, %d", f.Forfeits(), f.Removed()[itemId], inv.items[itemId], tc.removed, tc.held)
+			}
+
+			cq, err := characterquest.GetById(l, span, db)(characterId, questId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cq.Status() != characterquest.StatusNotStarted {
+				t.Fatalf("status = %s, want %s", cq.Status(), characterquest.StatusNotStarted)
+			}
+			passed, err := conversation.Passed(l, db)(characterId, questId, conversation.PhaseStart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if passed {
+				t.Fatalf("conversation of quest %d survived the forfeit", questId)
+			}
+			passed, err = conversation.Passed(l, db)(characterId, otherId, conversation.PhaseStart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !passed {
+				t.Fatalf("conversation of quest %d was cleared by forfeiting quest %d", otherId, questId)
+			}
+		})
+	}
+}
+
+func TestJobAdvancementNotForfeitable(t *testing.T) {
+	dir := copyQuestData(t)
+	rewrite(t, filepath.Join(dir, "Act.img.xml"), `<imgdir name="4513">
+        <imgdir name="0">
+        </imgdir>
+        <imgdir name="1">`, `<imgdir name="4513">
+        <imgdir name="0">
+        </imgdir>
+        <imgdir name="1">
+            <int name="job" value="110"/>`)
+	tm := tenant.NewModel("forfeit-test", dir, "", "")
+	wz.GetFileCache(tm.Id()).Init(dir)
+	err := GetCache(tm).Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		questId uint32
+		want    bool
+	}{
+		{4513, false},
+		{4522, true},
+		// quest 3803 restricts its action to a list of jobs, rather than advancing the character's job.
+		{3803, true},
+	} {
+		q, err := GetById(nil, tm)(tc.questId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := CanForfeit(tm, q); got != tc.want {
+			t.Errorf("CanForfeit(%d) = %t, want %t", tc.questId, got, tc.want)
+		}
+	}
+}
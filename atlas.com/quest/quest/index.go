@@ -0,0 +1,128 @@
+package quest
+
+import (
+	"sort"
+	"strings"
+)
+
+// index holds lookups over the loaded quests for searching. It is built alongside, and replaced with, the snapshot.
+type index struct {
+	ids            []uint16
+	names          map[uint16]string
+	byParent       map[string][]uint16
+	byArea         map[uint32][]uint16
+	byJob          map[uint16][]uint16
+	byMob          map[uint32][]uint16
+	byRewardItem   map[uint32][]uint16
+	byRequiredItem map[uint32][]uint16
+	byConsumedItem map[uint32][]uint16
+	skillBooks     []uint32
+}
+
+func newIndex(quests map[uint16]Model) *index {
+	i := &index{
+		ids:            make([]uint16, 0, len(quests)),
+		names:          make(map[uint16]string, len(quests)),
+		byParent:       make(map[string][]uint16),
+		byArea:         make(map[uint32][]uint16),
+		byJob:          make(map[uint16][]uint16),
+		byMob:          make(map[uint32][]uint16),
+		byRewardItem:   make(map[uint32][]uint16),
+		byRequiredItem: make(map[uint32][]uint16),
+		byConsumedItem: make(map[uint32][]uint16),
+		skillBooks:     make([]uint32, 0),
+	}
+	for id := range quests {
+		i.ids = append(i.ids, id)
+	}
+	sort.Slice(i.ids, func(a, b int) bool {
+		return i.ids[a] < i.ids[b]
+	})
+
+	for _, id := range i.ids {
+		q := quests[id]
+		i.names[id] = strings.ToLower(q.Name())
+		if q.Parent() != "" {
+			key := strings.ToLower(q.Parent())
+			i.byParent[key] = append(i.byParent[key], id)
+		}
+		i.byArea[q.Area()] = append(i.byArea[q.Area()], id)
+		for _, j := range q.Jobs() {
+			i.byJob[j] = appendQuest(i.byJob[j], id)
+		}
+		for _, m := range q.RelevantMobs() {
+			i.byMob[m] = appendQuest(i.byMob[m], id)
+		}
+		for _, it := range q.RewardItems() {
+			i.byRewardItem[it] = appendQuest(i.byRewardItem[it], id)
+		}
+		for _, it := range q.RequiredItems() {
+			i.byRequiredItem[it] = appendQuest(i.byRequiredItem[it], id)
+		}
+		for _, it := range q.ConsumedItems() {
+			i.byConsumedItem[it] = appendQuest(i.byConsumedItem[it], id)
+		}
+	}
+
+	for it := range i.byRewardItem {
+		if IsSkillBook(it) {
+			i.skillBooks = append(i.skillBooks, it)
+		}
+	}
+	sort.Slice(i.skillBooks, func(a, b int) bool {
+		return i.skillBooks[a] < i.skillBooks[b]
+	})
+	return i
+}
+
+func (i *index) itemQuests(itemId uint32) ItemQuests {
+	return ItemQuests{
+		itemId:   itemId,
+		required: orEmpty(i.byRequiredItem[itemId]),
+		rewarded: orEmpty(i.byRewardItem[itemId]),
+		consumed: orEmpty(i.byConsumedItem[itemId]),
+	}
+}
+
+func orEmpty(ids []uint16) []uint16 {
+	if ids == nil {
+		return make([]uint16, 0)
+	}
+	return ids
+}
+
+// appendItem adds id to the list of item ids, unless it is already present.
+func appendItem(ids []uint32, id uint32) []uint32 {
+	for _, o := range ids {
+		if o == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// appendQuest adds id to the ascending list of quest ids, unless it is already the last entry.
+func appendQuest(ids []uint16, id uint16) []uint16 {
+	if len(ids) > 0 && ids[len(ids)-1] == id {
+		return ids
+	}
+	return append(ids, id)
+}
+
+// intersect returns the ids present in both ascending lists.
+func intersect(a []uint16, b []uint16) []uint16 {
+	results := make([]uint16, 0)
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			results = append(results, a[i])
+			i++
+			j++
+		} else if a[i] < b[j] {
+			i++
+		} else {
+			j++
+		}
+	}
+	return results
+}
@@ -0,0 +1,124 @@
+package quest
+
+const (
+	skillBookCategory   = 228
+	masteryBookCategory = 229
+	questItemCategory   = 403
+)
+
+// IsQuestItem reports whether the item is of the category only ever held on behalf of a quest.
+func IsQuestItem(itemId uint32) bool {
+	return itemId/10000 == questItemCategory
+}
+
+// IsSkillBook reports whether the item is a skill or mastery book.
+func IsSkillBook(itemId uint32) bool {
+	c := itemId / 10000
+	return c == skillBookCategory || c == masteryBookCategory
+}
+
+// ItemUsage describes the part an item plays in a quest.
+type ItemUsage struct {
+	questId       uint16
+	itemId        uint32
+	startCount    int32
+	completeCount int32
+	granted       int32
+	startGranted  int32
+	taken         int32
+}
+
+func (u ItemUsage) QuestId() uint16 {
+	return u.questId
+}
+
+func (u ItemUsage) ItemId() uint32 {
+	return u.itemId
+}
+
+// StartCount is the quantity the character must hold to start the quest.
+func (u ItemUsage) StartCount() int32 {
+	return u.startCount
+}
+
+// CompleteCount is the quantity the character must hold to complete the quest.
+func (u ItemUsage) CompleteCount() int32 {
+	return u.completeCount
+}
+
+// Granted is the quantity given to the character on starting and completing the quest.
+func (u ItemUsage) Granted() int32 {
+	return u.granted
+}
+
+// StartGranted is the part of the granted quantity given to the character on starting the quest.
+func (u ItemUsage) StartGranted() int32 {
+	return u.startGranted
+}
+
+// Taken is the quantity removed from the character on starting and completing the quest.
+func (u ItemUsage) Taken() int32 {
+	return u.taken
+}
+
+// IsRequired reports whether the item is named by a start or completion requirement.
+func (u ItemUsage) IsRequired() bool {
+	return u.startCount > 0 || u.completeCount > 0
+}
+
+func (u ItemUsage) IsRewarded() bool {
+	return u.granted > 0
+}
+
+// IsGivenOnStart reports whether the item is handed out by a start action.
+func (u ItemUsage) IsGivenOnStart() bool {
+	return u.startGranted > 0
+}
+
+func (u ItemUsage) IsConsumed() bool {
+	return u.taken > 0
+}
+
+// ItemQuests lists, in ascending order, the quests making use of an item.
+type ItemQuests struct {
+	itemId   uint32
+	required []uint16
+	rewarded []uint16
+	consumed []uint16
+}
+
+func (q ItemQuests) ItemId() uint32 {
+	return q.itemId
+}
+
+// Required lists the quests requiring the item to be started or completed.
+func (q ItemQuests) Required() []uint16 {
+	return q.required
+}
+
+// Rewarded lists the quests granting the item.
+func (q ItemQuests) Rewarded() []uint16 {
+	return q.rewarded
+}
+
+// Consumed lists the quests taking the item away.
+func (q ItemQuests) Consumed() []uint16 {
+	return q.consumed
+}
+
+// UsedOnlyBy reports whether the quest is the only one to make use of the item.
+func (q ItemQuests) UsedOnlyBy(questId uint16) bool {
+	for _, ids := range [][]uint16{q.required, q.rewarded, q.consumed} {
+		for _, id := range ids {
+			if id != questId {
+				return false
+			}
+		}
+	}
+	return q.Used()
+}
+
+// Used reports whether any quest makes use of the item.
+func (q ItemQuests) Used() bool {
+	return len(q.required) > 0 || len(q.rewarded) > 0 || len(q.consumed) > 0
+}
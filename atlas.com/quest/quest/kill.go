@@ -0,0 +1,134 @@
+package quest
+
+import (
+	"atlas-quest/character"
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/sharing"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// PartyMember is a member of the killer's party, along with the map they are in. A zero map id leaves the map to be
+// looked up.
+type PartyMember struct {
+	characterId uint32
+	mapId       uint32
+}
+
+func NewPartyMember(characterId uint32, mapId uint32) PartyMember {
+	return PartyMember{characterId: characterId, mapId: mapId}
+}
+
+func (m PartyMember) CharacterId() uint32 {
+	return m.characterId
+}
+
+func (m PartyMember) MapId() uint32 {
+	return m.mapId
+}
+
+// RecordKill credits the kill of the monster in the map towards the started quests requiring it. The killer is always
+// credited. Party members in the same map are credited as well, for quests configured to share kills. The progress
+// made is returned, ordered by character and quest.
+func RecordKill(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(killerId uint32, mobId uint32, mapId uint32, party []PartyMember) ([]Progress, error) {
+	return func(killerId uint32, mobId uint32, mapId uint32, party []PartyMember) ([]Progress, error) {
+		results := make([]Progress, 0)
+		candidates := getKillQuests(t, mobId)
+		if len(candidates) == 0 {
+			return results, nil
+		}
+
+		ps, err := creditKill(l, span, db, t, candidates)(killerId, mobId, false)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, ps...)
+
+		shared := false
+		for id := range candidates {
+			if sharing.GetRegistry().IsShared(t.Id(), id) {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			return results, nil
+		}
+
+		credited := map[uint32]bool{killerId: true}
+		for _, m := range party {
+			if credited[m.CharacterId()] {
+				continue
+			}
+			credited[m.CharacterId()] = true
+			if !inRange(l, span, t)(m, mapId) {
+				continue
+			}
+
+			ps, err = creditKill(l, span, db, t, candidates)(m.CharacterId(), mobId, true)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to share kill of monster %d with party member %d.", mobId, m.CharacterId())
+				continue
+			}
+			results = append(results, ps...)
+		}
+		return results, nil
+	}
+}
+
+// inRange reports whether the party member is in the map the kill was made in.
+func inRange(l logrus.FieldLogger, span opentracing.Span, t tenant.Model) func(m PartyMember, mapId uint32) bool {
+	return func(m PartyMember, mapId uint32) bool {
+		if m.MapId() != 0 {
+			return m.MapId() == mapId
+		}
+		return character.InMap(l, span, t)(m.CharacterId(), mapId)
+	}
+}
+
+// creditKill applies the kill to the character's started quests amongst the candidates whose requirement of the monster
+// has yet to be met. Should the kill be shared, only quests configured to share kills are credited.
+func creditKill(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model, candidates map[uint16]Model) func(characterId uint32, mobId uint32, shared bool) ([]Progress, error) {
+	return func(characterId uint32, mobId uint32, shared bool) ([]Progress, error) {
+		started, err := characterquest.QuestsByStatus(l, span, db)(characterId, characterquest.StatusStarted)
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]Progress, 0)
+		for _, s := range started {
+			q, ok := candidates[s.Id()]
+			if !ok || (shared && !sharing.GetRegistry().IsShared(t.Id(), q.Id())) {
+				continue
+			}
+			required, _ := q.KillsRequired(mobId)
+			kills, err := characterquest.GetKills(l, span, db)(characterId, q.Id())
+			if err != nil {
+				return nil, err
+			}
+			if kills[mobId] >= required {
+				continue
+			}
+			p, err := characterquest.RecordKills(l, db)(characterId, q.Id(), mobId, 1, required)
+			if err != nil {
+				return nil, err
+			}
+			kills[mobId] = p.Count()
+			results = append(results, Progress{
+				characterId: characterId,
+				questId:     q.Id(),
+				mobId:       mobId,
+				count:       p.Count(),
+				required:    required,
+				encoded:     EncodeRecord(q, kills, s.Info()),
+			})
+		}
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].questId < results[j].questId
+		})
+		return results, nil
+	}
+}
@@ -0,0 +1,100 @@
+package quest
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/sharing"
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordKillSharing(t *testing.T) {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	tm := tenant.NewModel("kill-test", wzDir, "", "")
+	wz.GetFileCache(tm.Id()).Init(wzDir)
+	if err := GetCache(tm).Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// both 4511 and 4523 require monster 9420511, of which only 4511 shares kills.
+	path := filepath.Join(t.TempDir(), "sharing.json")
+	if err := os.WriteFile(path, []byte(`{"shared": true, "quests": {"4523": false}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := sharing.GetRegistry().Init(tm.Id(), path); err != nil {
+		t.Fatal(err)
+	}
+
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan("record-kill")
+	db := openDatabase(t)
+
+	const (
+		mobId   = uint32(9420511)
+		mapId   = uint32(600000000)
+		killer  = uint32(1)
+		member  = uint32(2)
+		distant = uint32(3)
+	)
+	for _, characterId := range []uint32{killer, member, distant} {
+		for _, questId := range []uint16{4511, 4523} {
+			if _, err := characterquest.Start(l, db)(characterId, questId, characterquest.StatusNotStarted); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	party := []PartyMember{
+		NewPartyMember(killer, mapId),
+		NewPartyMember(member, mapId),
+		NewPartyMember(member, mapId),
+		NewPartyMember(distant, mapId+1),
+	}
+	ps, err := RecordKill(l, span, db, tm)(killer, mobId, mapId, party)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type credit struct {
+		characterId uint32
+		questId     uint16
+	}
+	want := []credit{{killer, 4511}, {killer, 4523}, {member, 4511}}
+	if len(ps) != len(want) {
+		t.Fatalf("RecordKill credited %d quests, want %d", len(ps), len(want))
+	}
+	for i, p := range ps {
+		if p.CharacterId() != want[i].characterId || p.QuestId() != want[i].questId || p.Count() != 1 {
+			t.Errorf("progress %d = character %d quest %d count %d, want character %d quest %d count 1", i, p.CharacterId(), p.QuestId(), p.Count(), want[i].characterId, want[i].questId)
+		}
+	}
+
+	for _, tc := range []struct {
+		characterId uint32
+		questId     uint16
+		want        uint32
+	}{
+		{killer, 4511, 1},
+		{killer, 4523, 1},
+		{member, 4511, 1},
+		{member, 4523, 0},
+		{distant, 4511, 0},
+		{distant, 4523, 0},
+	} {
+		kills, err := characterquest.GetKills(l, span, db)(tc.characterId, tc.questId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kills[mobId] != tc.want {
+			t.Errorf("character %d has %d kills towards quest %d, want %d", tc.characterId, kills[mobId], tc.questId, tc.want)
+		}
+	}
+}
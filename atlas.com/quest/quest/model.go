@@ -2,7 +2,11 @@ package quest
 
 import (
 	"atlas-quest/quest/action"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/event"
 	"atlas-quest/quest/requirement"
+	"sort"
+	"time"
 )
 
 type Model struct {
@@ -15,18 +19,252 @@ type Model struct {
 	autoPreComplete      bool
 	autoComplete         bool
 	repeatable           bool
+	jobAdvancement       bool
 	medalId              uint32
+	area                 uint32
+	order                uint32
+	sortKey              uint32
+	questType            string
+	summary              string
+	demandSummary        string
+	rewardSummary        string
+	selectedMob          bool
+	selectedSkillId      uint32
+	medalCategory        uint32
+	autoAccept           bool
+	timerUI              string
+	showLayerTag         string
+	oneShot              bool
+	dailyPlayTime        uint32
+	startDescription     string
+	progressDescription  string
+	completeDescription  string
+	startConversation    conversation.Model
+	completeConversation conversation.Model
 	startRequirements    map[requirement.Type]requirement.CheckFunc
 	completeRequirements map[requirement.Type]requirement.CheckFunc
 	startActions         map[action.Type]Action
 	completeActions      map[action.Type]Action
 	relevantMobs         []uint32
+	kills                []requirement.Mob
+	scripts              []string
+	eventStart           time.Time
+	eventEnd             time.Time
+	minLevel             byte
+	maxLevel             byte
+	jobs                 []uint16
+	items                map[uint32]ItemUsage
 }
 
 func (m *Model) Id() uint16 {
 	return m.id
 }
 
+func (m *Model) Name() string {
+	return m.name
+}
+
+func (m *Model) Parent() string {
+	return m.parent
+}
+
+func (m *Model) TimeLimit() uint32 {
+	return m.timeLimit
+}
+
+func (m *Model) TimeLimit2() uint32 {
+	return m.timeLimit2
+}
+
+func (m *Model) AutoStart() bool {
+	return m.autoStart
+}
+
+func (m *Model) AutoPreComplete() bool {
+	return m.autoPreComplete
+}
+
+func (m *Model) AutoComplete() bool {
+	return m.autoComplete
+}
+
+func (m *Model) Repeatable() bool {
+	return m.repeatable
+}
+
+// JobAdvancement reports whether starting or completing the quest advances the character to another job.
+func (m *Model) JobAdvancement() bool {
+	return m.jobAdvancement
+}
+
+func (m *Model) MedalId() uint32 {
+	return m.medalId
+}
+
+func (m *Model) Area() uint32 {
+	return m.area
+}
+
+func (m *Model) Order() uint32 {
+	return m.order
+}
+
+func (m *Model) SortKey() uint32 {
+	return m.sortKey
+}
+
+func (m *Model) Type() string {
+	return m.questType
+}
+
+func (m *Model) Summary() string {
+	return m.summary
+}
+
+func (m *Model) DemandSummary() string {
+	return m.demandSummary
+}
+
+func (m *Model) RewardSummary() string {
+	return m.rewardSummary
+}
+
+func (m *Model) SelectedMob() bool {
+	return m.selectedMob
+}
+
+func (m *Model) SelectedSkillId() uint32 {
+	return m.selectedSkillId
+}
+
+func (m *Model) MedalCategory() uint32 {
+	return m.medalCategory
+}
+
+func (m *Model) AutoAccept() bool {
+	return m.autoAccept
+}
+
+func (m *Model) TimerUI() string {
+	return m.timerUI
+}
+
+func (m *Model) ShowLayerTag() string {
+	return m.showLayerTag
+}
+
+func (m *Model) OneShot() bool {
+	return m.oneShot
+}
+
+func (m *Model) DailyPlayTime() uint32 {
+	return m.dailyPlayTime
+}
+
+func (m *Model) StartDescription() string {
+	return m.startDescription
+}
+
+func (m *Model) ProgressDescription() string {
+	return m.progressDescription
+}
+
+func (m *Model) CompleteDescription() string {
+	return m.completeDescription
+}
+
+// Scripts lists the names of the scripts the quest requirements invoke.
+func (m *Model) Scripts() []string {
+	return m.scripts
+}
+
+func (m *Model) RelevantMobs() []uint32 {
+	return m.relevantMobs
+}
+
+// Kills lists the monsters to be killed in order to complete the quest, in the order the client tracks them.
+func (m *Model) Kills() []requirement.Mob {
+	return m.kills
+}
+
+// KillsRequired is the number of the monster the quest requires be killed for completion.
+func (m *Model) KillsRequired(mobId uint32) (uint32, bool) {
+	for _, k := range m.kills {
+		if k.Id() == mobId {
+			return k.Count(), true
+		}
+	}
+	return 0, false
+}
+
+// MinLevel is the level a character must have reached to start the quest, or zero if there is none.
+func (m *Model) MinLevel() byte {
+	return m.minLevel
+}
+
+// MaxLevel is the level a character may not exceed to start the quest, or zero if there is none.
+func (m *Model) MaxLevel() byte {
+	return m.maxLevel
+}
+
+// Jobs lists the jobs permitted to start the quest. An empty list permits all.
+func (m *Model) Jobs() []uint16 {
+	return m.jobs
+}
+
+// RequiredItems lists, in ascending order, the items the quest requires in order to be started or completed.
+func (m *Model) RequiredItems() []uint32 {
+	return m.itemIds(ItemUsage.IsRequired)
+}
+
+// RewardItems lists, in ascending order, the items granted on starting or completing the quest.
+func (m *Model) RewardItems() []uint32 {
+	return m.itemIds(ItemUsage.IsRewarded)
+}
+
+// StartItems lists, in ascending order, the items granted on starting the quest.
+func (m *Model) StartItems() []uint32 {
+	return m.itemIds(ItemUsage.IsGivenOnStart)
+}
+
+// ConsumedItems lists, in ascending order, the items taken away on starting or completing the quest.
+func (m *Model) ConsumedItems() []uint32 {
+	return m.itemIds(ItemUsage.IsConsumed)
+}
+
+func (m *Model) itemIds(filter func(ItemUsage) bool) []uint32 {
+	results := make([]uint32, 0)
+	for id, u := range m.items {
+		if filter(u) {
+			results = append(results, id)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i] < results[j]
+	})
+	return results
+}
+
+// ItemUsage describes the part the item plays in the quest. The second result is false should the quest make no use of
+// the item.
+func (m *Model) ItemUsage(itemId uint32) (ItemUsage, bool) {
+	u, ok := m.items[itemId]
+	return u, ok
+}
+
+// EventWindow is the period the quest data restricts starting the quest to, prior to any operator override.
+func (m *Model) EventWindow() event.Window {
+	return event.NewWindow(m.id, m.eventStart, m.eventEnd)
+}
+
+func (m *Model) StartConversation() conversation.Model {
+	return m.startConversation
+}
+
+func (m *Model) CompleteConversation() conversation.Model {
+	return m.completeConversation
+}
+
 type ModelBuilder struct {
 	id                   uint16
 	name                 string
@@ -37,12 +275,41 @@ type ModelBuilder struct {
 	autoPreComplete      bool
 	autoComplete         bool
 	repeatable           bool
+	jobAdvancement       bool
 	medalId              uint32
+	area                 uint32
+	order                uint32
+	sortKey              uint32
+	questType            string
+	summary              string
+	demandSummary        string
+	rewardSummary        string
+	selectedMob          bool
+	selectedSkillId      uint32
+	medalCategory        uint32
+	autoAccept           bool
+	timerUI              string
+	showLayerTag         string
+	oneShot              bool
+	dailyPlayTime        uint32
+	startDescription     string
+	progressDescription  string
+	completeDescription  string
+	startConversation    conversation.Model
+	completeConversation conversation.Model
 	startRequirements    map[requirement.Type]requirement.CheckFunc
 	completeRequirements map[requirement.Type]requirement.CheckFunc
 	startActions         map[action.Type]Action
 	completeActions      map[action.Type]Action
 	relevantMobs         []uint32
+	kills                []requirement.Mob
+	scripts              []string
+	eventStart           time.Time
+	eventEnd             time.Time
+	minLevel             byte
+	maxLevel             byte
+	jobs                 []uint16
+	items                map[uint32]ItemUsage
 }
 
 type Action struct {
@@ -58,6 +325,10 @@ func NewBuilder(id uint16) *ModelBuilder {
 		startActions:         make(map[action.Type]Action),
 		completeActions:      make(map[action.Type]Action),
 		relevantMobs:         make([]uint32, 0),
+		kills:                make([]requirement.Mob, 0),
+		scripts:              make([]string, 0),
+		jobs:                 make([]uint16, 0),
+		items:                make(map[uint32]ItemUsage),
 	}
 }
 
@@ -66,6 +337,11 @@ func (m *ModelBuilder) SetRepeatable(value bool) *ModelBuilder {
 	return m
 }
 
+func (m *ModelBuilder) SetJobAdvancement(value bool) *ModelBuilder {
+	m.jobAdvancement = value
+	return m
+}
+
 func (m *ModelBuilder) AddStartingRequirement(t requirement.Type, rcf requirement.CheckFunc) *ModelBuilder {
 	m.startRequirements[t] = rcf
 	return m
@@ -81,6 +357,22 @@ func (m *ModelBuilder) AddRelevantMob(id uint32) *ModelBuilder {
 	return m
 }
 
+// AddKills records the monsters to be killed in order to complete the quest, in the order the client tracks them.
+func (m *ModelBuilder) AddKills(mobs ...requirement.Mob) *ModelBuilder {
+	m.kills = append(m.kills, mobs...)
+	return m
+}
+
+func (m *ModelBuilder) AddScript(name string) *ModelBuilder {
+	for _, s := range m.scripts {
+		if s == name {
+			return m
+		}
+	}
+	m.scripts = append(m.scripts, name)
+	return m
+}
+
 func (m *ModelBuilder) Build() Model {
 	return Model{
 		id:                   m.id,
@@ -92,12 +384,41 @@ func (m *ModelBuilder) Build() Model {
 		autoPreComplete:      m.autoPreComplete,
 		autoComplete:         m.autoComplete,
 		repeatable:           m.repeatable,
+		jobAdvancement:       m.jobAdvancement,
 		medalId:              m.medalId,
+		area:                 m.area,
+		order:                m.order,
+		sortKey:              m.sortKey,
+		questType:            m.questType,
+		summary:              m.summary,
+		demandSummary:        m.demandSummary,
+		rewardSummary:        m.rewardSummary,
+		selectedMob:          m.selectedMob,
+		selectedSkillId:      m.selectedSkillId,
+		medalCategory:        m.medalCategory,
+		autoAccept:           m.autoAccept,
+		timerUI:              m.timerUI,
+		showLayerTag:         m.showLayerTag,
+		oneShot:              m.oneShot,
+		dailyPlayTime:        m.dailyPlayTime,
+		startDescription:     m.startDescription,
+		progressDescription:  m.progressDescription,
+		completeDescription:  m.completeDescription,
+		startConversation:    m.startConversation,
+		completeConversation: m.completeConversation,
 		startRequirements:    m.startRequirements,
 		completeRequirements: m.completeRequirements,
 		startActions:         m.startActions,
 		completeActions:      m.completeActions,
 		relevantMobs:         m.relevantMobs,
+		kills:                m.kills,
+		scripts:              m.scripts,
+		eventStart:           m.eventStart,
+		eventEnd:             m.eventEnd,
+		minLevel:             m.minLevel,
+		maxLevel:             m.maxLevel,
+		jobs:                 m.jobs,
+		items:                m.items,
 	}
 }
 
@@ -133,6 +454,154 @@ func (m *ModelBuilder) SetMedalItem(value uint32) {
 	m.medalId = value
 }
 
+func (m *ModelBuilder) SetArea(value uint32) {
+	m.area = value
+}
+
+func (m *ModelBuilder) SetOrder(value uint32) {
+	m.order = value
+}
+
+func (m *ModelBuilder) SetSortKey(value uint32) {
+	m.sortKey = value
+}
+
+func (m *ModelBuilder) SetType(value string) {
+	m.questType = value
+}
+
+func (m *ModelBuilder) SetSummary(value string) {
+	m.summary = value
+}
+
+func (m *ModelBuilder) SetDemandSummary(value string) {
+	m.demandSummary = value
+}
+
+func (m *ModelBuilder) SetRewardSummary(value string) {
+	m.rewardSummary = value
+}
+
+func (m *ModelBuilder) SetSelectedMob(value bool) {
+	m.selectedMob = value
+}
+
+func (m *ModelBuilder) SetSelectedSkillId(value uint32) {
+	m.selectedSkillId = value
+}
+
+func (m *ModelBuilder) SetMedalCategory(value uint32) {
+	m.medalCategory = value
+}
+
+func (m *ModelBuilder) SetAutoAccept(value bool) {
+	m.autoAccept = value
+}
+
+func (m *ModelBuilder) SetTimerUI(value string) {
+	m.timerUI = value
+}
+
+func (m *ModelBuilder) SetShowLayerTag(value string) {
+	m.showLayerTag = value
+}
+
+func (m *ModelBuilder) SetOneShot(value bool) {
+	m.oneShot = value
+}
+
+func (m *ModelBuilder) SetDailyPlayTime(value uint32) {
+	m.dailyPlayTime = value
+}
+
+func (m *ModelBuilder) SetStartDescription(value string) {
+	m.startDescription = value
+}
+
+func (m *ModelBuilder) SetProgressDescription(value string) {
+	m.progressDescription = value
+}
+
+func (m *ModelBuilder) SetCompleteDescription(value string) {
+	m.completeDescription = value
+}
+
+func (m *ModelBuilder) SetEventStart(value time.Time) {
+	m.eventStart = value
+}
+
+func (m *ModelBuilder) SetEventEnd(value time.Time) {
+	m.eventEnd = value
+}
+
+func (m *ModelBuilder) SetMinLevel(value byte) {
+	m.minLevel = value
+}
+
+func (m *ModelBuilder) SetMaxLevel(value byte) {
+	m.maxLevel = value
+}
+
+func (m *ModelBuilder) SetJobs(value []uint16) {
+	m.jobs = value
+}
+
+func (m *ModelBuilder) itemUsage(itemId uint32) ItemUsage {
+	if u, ok := m.items[itemId]; ok {
+		return u
+	}
+	return ItemUsage{questId: m.id, itemId: itemId}
+}
+
+// AddStartItem records the quantity of the item required to start the quest.
+func (m *ModelBuilder) AddStartItem(itemId uint32, count int32) {
+	u := m.itemUsage(itemId)
+	u.startCount += count
+	m.items[itemId] = u
+}
+
+// AddCompleteItem records the quantity of the item required to complete the quest.
+func (m *ModelBuilder) AddCompleteItem(itemId uint32, count int32) {
+	u := m.itemUsage(itemId)
+	u.completeCount += count
+	m.items[itemId] = u
+}
+
+// AddActionItem records an item given by a start or completion action, should count be positive, or taken away, should
+// it be negative.
+func (m *ModelBuilder) AddActionItem(itemId uint32, count int32) {
+	u := m.itemUsage(itemId)
+	if count > 0 {
+		u.granted += count
+	} else {
+		u.taken -= count
+	}
+	m.items[itemId] = u
+}
+
+// AddStartActionItem records an item given or taken away by a start action, as AddActionItem does, noting should it be
+// given.
+func (m *ModelBuilder) AddStartActionItem(itemId uint32, count int32) {
+	m.AddActionItem(itemId, count)
+	if count > 0 {
+		u := m.items[itemId]
+		u.startGranted += count
+		m.items[itemId] = u
+	}
+}
+
+func (m *ModelBuilder) EventWindow() event.Window {
+	return event.NewWindow(m.id, m.eventStart, m.eventEnd)
+}
+
+func (m *ModelBuilder) SetStartConversation(value conversation.Model) {
+	m.startConversation = value
+}
+
+func (m *ModelBuilder) SetCompleteConversation(value conversation.Model) {
+	m.completeConversation = value
+}
+
 func (m *ModelBuilder) AddStartingAction(t action.Type, check action.CheckFunc, run action.RunFunc) {
 	m.startActions[t] = Action{check: check, run: run}
 }
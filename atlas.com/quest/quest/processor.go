@@ -1,9 +1,259 @@
 package quest
 
-import "github.com/sirupsen/logrus"
+import (
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/quest/event"
+	"atlas-quest/quest/script"
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
 
-func GetById(l logrus.FieldLogger) func(id uint32) (*Model, error) {
-	return func(id uint32) (*Model, error) {
-		return nil, nil
+func GetById(_ logrus.FieldLogger, t tenant.Model) func(id uint32) (Model, error) {
+	return func(id uint32) (Model, error) {
+		if id > math.MaxUint16 {
+			return Model{}, NotFoundError{Id: id}
+		}
+		return GetCache(t).GetQuest(uint16(id))
+	}
+}
+
+func GetDiagnostics(_ logrus.FieldLogger, t tenant.Model) []diagnostic.Model {
+	return GetCache(t).GetDiagnostics()
+}
+
+// DependentCache is a cache derived from the same WZ data as the quests, which must be reloaded along with them.
+type DependentCache struct {
+	name string
+	init func(t tenant.Model) error
+}
+
+var dependents []DependentCache
+var dependentsLock sync.RWMutex
+
+// RegisterDependentCache adds a cache to be initialized alongside the quest cache of every tenant.
+func RegisterDependentCache(name string, init func(t tenant.Model) error) {
+	dependentsLock.Lock()
+	defer dependentsLock.Unlock()
+	dependents = append(dependents, DependentCache{name: name, init: init})
+}
+
+// InitDependentCaches initializes every registered dependent cache of the tenant. All are attempted, and the first
+// failure is returned.
+func InitDependentCaches(l logrus.FieldLogger, t tenant.Model) error {
+	dependentsLock.RLock()
+	ds := append([]DependentCache{}, dependents...)
+	dependentsLock.RUnlock()
+
+	var result error
+	for _, d := range ds {
+		err := d.init(t)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to load %s cache of tenant [%s].", d.name, t.Id())
+			if result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
+// Reload re-reads the WZ directory and rebuilds the quest cache, followed by every dependent cache. The existing quest
+// cache remains in use if this fails, or if the new data regresses from it and the reload is not forced.
+func Reload(l logrus.FieldLogger, t tenant.Model, force bool) error {
+	err := wz.GetFileCache(t.Id()).Refresh()
+	if err != nil {
+		l.WithError(err).Errorf("Unable to refresh WZ file listing of tenant [%s].", t.Id())
+		return err
+	}
+	before := len(GetCache(t).GetDiagnostics())
+	if force {
+		err = GetCache(t).ForceReload()
+	} else {
+		err = GetCache(t).Reload()
+	}
+	if err != nil {
+		l.WithError(err).Errorf("Unable to reload quest cache of tenant [%s], retaining existing data.", t.Id())
+		return err
+	}
+	after := len(GetCache(t).GetDiagnostics())
+	if after > before {
+		l.Warnf("Reloaded quest cache of tenant [%s] reports %d diagnostics, up from %d. See /quests/diagnostics for details.", t.Id(), after, before)
+	} else {
+		l.Infof("Reloaded quest cache of tenant [%s] with %d diagnostics.", t.Id(), after)
+	}
+	ReportUnregisteredScripts(l, t)
+	return InitDependentCaches(l, t)
+}
+
+// Search retrieves a page of the quests satisfying all the criteria, in ascending id order, along with the total number
+// of quests which do.
+func Search(_ logrus.FieldLogger, t tenant.Model) func(offset int, limit int, filters ...Criteria) ([]Model, int) {
+	return func(offset int, limit int, filters ...Criteria) ([]Model, int) {
+		qs := GetCache(t).Search(filters...)
+		total := len(qs)
+		if offset < 0 {
+			offset = 0
+		}
+		if offset >= total || limit <= 0 {
+			return make([]Model, 0), total
+		}
+		end := total
+		if limit < total-offset {
+			end = offset + limit
+		}
+		return qs[offset:end], total
+	}
+}
+
+// GetItemUsage retrieves the part the item plays in the quest.
+func GetItemUsage(l logrus.FieldLogger, t tenant.Model) func(questId uint32, itemId uint32) (ItemUsage, error) {
+	return func(questId uint32, itemId uint32) (ItemUsage, error) {
+		q, err := GetById(l, t)(questId)
+		if err != nil {
+			return ItemUsage{}, err
+		}
+		u, ok := q.ItemUsage(itemId)
+		if !ok {
+			return ItemUsage{}, errors.New(fmt.Sprintf("quest %d makes no use of item %d", questId, itemId))
+		}
+		return u, nil
+	}
+}
+
+// GetItemQuests retrieves the quests which require, reward or consume the item.
+func GetItemQuests(_ logrus.FieldLogger, t tenant.Model) func(itemId uint32) ItemQuests {
+	return func(itemId uint32) ItemQuests {
+		return GetCache(t).GetItemQuests(itemId)
+	}
+}
+
+// GetSkillBooks retrieves the skill and mastery books granted by quests, along with the quests granting each.
+func GetSkillBooks(_ logrus.FieldLogger, t tenant.Model) []ItemQuests {
+	return GetCache(t).GetSkillBooks()
+}
+
+// GetConversation retrieves the dialogue held by the quest for the phase, either conversation.PhaseStart or
+// conversation.PhaseComplete.
+func GetConversation(l logrus.FieldLogger, t tenant.Model) func(questId uint32, phase string) (conversation.Model, error) {
+	return func(questId uint32, phase string) (conversation.Model, error) {
+		q, err := GetById(l, t)(questId)
+		if err != nil {
+			return conversation.Model{}, err
+		}
+		switch phase {
+		case conversation.PhaseStart:
+			return q.StartConversation(), nil
+		case conversation.PhaseComplete:
+			return q.CompleteConversation(), nil
+		}
+		return conversation.Model{}, errors.New(fmt.Sprintf("unknown conversation phase %s", phase))
+	}
+}
+
+// SubmitConversation validates the character's answers to the quest conversation. Whether it was passed is retained,
+// and governs if the character may proceed with the phase.
+func SubmitConversation(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint32, phase string, selections []uint32, accepted bool) (conversation.Result, error) {
+	return func(characterId uint32, questId uint32, phase string, selections []uint32, accepted bool) (conversation.Result, error) {
+		m, err := GetConversation(l, t)(questId, phase)
+		if err != nil {
+			return conversation.Result{}, err
+		}
+		return conversation.Submit(l, db)(characterId, uint16(questId), phase, m, selections, accepted)
+	}
+}
+
+// ScriptReference is a script named by the quest data, along with the quests which invoke it.
+type ScriptReference struct {
+	name       string
+	registered bool
+	quests     []uint16
+}
+
+func (r ScriptReference) Name() string {
+	return r.name
+}
+
+func (r ScriptReference) Registered() bool {
+	return r.registered
+}
+
+func (r ScriptReference) Quests() []uint16 {
+	return r.quests
+}
+
+// GetScripts lists the scripts referenced by the loaded quests, and whether a hook has been registered for each.
+func GetScripts(_ logrus.FieldLogger, t tenant.Model) []ScriptReference {
+	results := make([]ScriptReference, 0)
+	for name, qs := range GetCache(t).GetScripts() {
+		results = append(results, ScriptReference{name: name, registered: script.GetRegistry().IsRegistered(t.Id(), name), quests: qs})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].name < results[j].name
+	})
+	return results
+}
+
+// ReportUnregisteredScripts logs the scripts referenced by the loaded quests which have no registered hook.
+func ReportUnregisteredScripts(l logrus.FieldLogger, t tenant.Model) {
+	count := 0
+	for _, r := range GetScripts(l, t) {
+		if !r.Registered() {
+			l.Debugf("Script [%s] referenced by quests %v is not registered.", r.Name(), r.Quests())
+			count++
+		}
+	}
+	if count > 0 && t.PermitUnregisteredScripts() {
+		l.Warnf("%d scripts referenced by quests are not registered, and are allowed to pass. See /quests/scripts for details.", count)
+	} else if count > 0 {
+		l.Warnf("%d scripts referenced by quests are not registered, and fail until they are. See /quests/scripts for details.", count)
+	}
+}
+
+// GetEventWindows retrieves the windows of the seasonal quests which are active or upcoming at now, taking operator
+// overrides into account. Windows are ordered by start.
+func GetEventWindows(_ logrus.FieldLogger, t tenant.Model) func(now time.Time) []event.Window {
+	return func(now time.Time) []event.Window {
+		results := make([]event.Window, 0)
+		for _, q := range GetCache(t).GetQuests() {
+			w := event.GetRegistry().Resolve(t.Id(), q.EventWindow())
+			if !w.Bounded() || w.Status(now) == event.StatusEnded {
+				continue
+			}
+			results = append(results, w)
+		}
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].Start().Equal(results[j].Start()) {
+				return results[i].QuestId() < results[j].QuestId()
+			}
+			return results[i].Start().Before(results[j].Start())
+		})
+		return results
+	}
+}
+
+// OverrideEventWindow replaces the window in which the quest may be started. A zero start or end leaves the window
+// open on that side.
+func OverrideEventWindow(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(questId uint32, start time.Time, end time.Time) (event.Window, error) {
+	return func(questId uint32, start time.Time, end time.Time) (event.Window, error) {
+		_, err := GetById(l, t)(questId)
+		if err != nil {
+			return event.Window{}, err
+		}
+		return event.Override(l, db, t)(uint16(questId), start, end)
+	}
+}
+
+func ClearEventWindow(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(questId uint32) error {
+	return func(questId uint32) error {
+		return event.ClearOverride(l, db, t)(uint16(questId))
 	}
 }
@@ -0,0 +1,128 @@
+package quest
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/record"
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// Progress is the character's standing towards the kills a quest requires of a monster.
+type Progress struct {
+	characterId uint32
+	questId     uint16
+	mobId       uint32
+	count       uint32
+	required    uint32
+	encoded     string
+}
+
+func (p Progress) CharacterId() uint32 {
+	return p.characterId
+}
+
+func (p Progress) QuestId() uint16 {
+	return p.questId
+}
+
+func (p Progress) MobId() uint32 {
+	return p.mobId
+}
+
+func (p Progress) Count() uint32 {
+	return p.count
+}
+
+func (p Progress) Required() uint32 {
+	return p.required
+}
+
+// Encoded is the progress string the client displays for the quest, covering all the monsters it requires.
+func (p Progress) Encoded() string {
+	return p.encoded
+}
+
+// GetMobProgress retrieves the character's progress in the started quests which a kill of the monster counts towards.
+// Quests whose requirement of the monster has already been met are not included. The character's quests are only
+// consulted when some quest requires the monster.
+func GetMobProgress(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, mobId uint32) ([]Progress, error) {
+	return func(characterId uint32, mobId uint32) ([]Progress, error) {
+		results := make([]Progress, 0)
+		candidates := getKillQuests(t, mobId)
+		if len(candidates) == 0 {
+			return results, nil
+		}
+
+		started, err := characterquest.QuestsByStatus(l, span, db)(characterId, characterquest.StatusStarted)
+		if err != nil {
+			return nil, err
+		}
+		ps, err := characterquest.GetProgress(l, span, db)(characterId)
+		if err != nil {
+			return nil, err
+		}
+		kills := make(map[uint16]map[uint32]uint32)
+		for _, p := range ps {
+			if _, ok := kills[p.QuestId()]; !ok {
+				kills[p.QuestId()] = make(map[uint32]uint32)
+			}
+			kills[p.QuestId()][p.MobId()] = p.Count()
+		}
+
+		for _, s := range started {
+			q, ok := candidates[s.Id()]
+			if !ok {
+				continue
+			}
+			required, _ := q.KillsRequired(mobId)
+			count := kills[q.Id()][mobId]
+			if count >= required {
+				continue
+			}
+			results = append(results, Progress{
+				characterId: characterId,
+				questId:     q.Id(),
+				mobId:       mobId,
+				count:       count,
+				required:    required,
+				encoded:     EncodeRecord(q, kills[q.Id()], s.Info()),
+			})
+		}
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].questId < results[j].questId
+		})
+		return results, nil
+	}
+}
+
+// getKillQuests retrieves the quests requiring the monster to be killed for completion, keyed by quest id.
+func getKillQuests(t tenant.Model, mobId uint32) map[uint16]Model {
+	results := make(map[uint16]Model)
+	for _, q := range GetCache(t).Search(RelevantTo(mobId)) {
+		if _, ok := q.KillsRequired(mobId); ok {
+			results[q.Id()] = q
+		}
+	}
+	return results
+}
+
+// EncodeRecord produces the client quest record of the quest, given the kills made towards it keyed by monster id.
+func EncodeRecord(q Model, kills map[uint32]uint32, info string) string {
+	ks := make([]record.Kill, 0, len(q.Kills()))
+	for _, k := range q.Kills() {
+		ks = append(ks, record.NewKill(k.Id(), kills[k.Id()]))
+	}
+	return record.Encode(record.NewModel(ks, info))
+}
+
+// DecodeRecord reads a client quest record of the quest.
+func DecodeRecord(q Model, value string) (record.Model, error) {
+	ids := make([]uint32, 0, len(q.Kills()))
+	for _, k := range q.Kills() {
+		ids = append(ids, k.Id())
+	}
+	return record.Decode(ids, value)
+}
@@ -1,97 +1,124 @@
 package quest
 
 import (
+	"atlas-quest/character"
+	characterquest "atlas-quest/character/quest"
 	"atlas-quest/quest/action"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/quest/event"
+	"atlas-quest/quest/forfeit"
 	"atlas-quest/quest/requirement"
+	"atlas-quest/quest/world"
+	"atlas-quest/tenant"
 	"atlas-quest/wz"
 	"atlas-quest/xml"
 	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"strconv"
+	"strings"
+	"time"
 )
 
-func readQuests() ([]Model, error) {
-	qi, err := getQuestInfo()
+func readQuests(t tenant.Model, strict bool) ([]Model, []diagnostic.Model, error) {
+	qi, err := getQuestInfo(t)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	ci, err := getCheckInfo()
+	ci, err := getCheckInfo(t)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	ai, err := getActInfo()
+	ai, err := getActInfo(t)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	checks := childIndex(ci)
+	acts := childIndex(ai)
 	results := make([]Model, 0)
+	diagnostics := make([]diagnostic.Model, 0)
 	for _, cn := range qi.Children() {
 		questId, err := strconv.Atoi(cn.Name())
 		if err != nil {
-			return nil, err
+			d := diagnostic.NewSkipped(0, diagnostic.PhaseInfo, fmt.Sprintf("QuestInfo.img/%s", cn.Name()), err)
+			if strict {
+				return nil, nil, d
+			}
+			diagnostics = append(diagnostics, d)
+			continue
+		}
+		q, ds, err := createQuest(t, uint16(questId), cn, checks[strconv.Itoa(questId)], acts[strconv.Itoa(questId)])
+		if strict && len(ds) > 0 {
+			return nil, nil, ds[0]
 		}
-		q, err := createQuest(uint16(questId), cn, ci, ai)
+		diagnostics = append(diagnostics, ds...)
 		if err != nil {
-			return nil, err
+			continue
 		}
 		results = append(results, q)
 	}
-	return results, nil
+	return results, diagnostics, nil
 }
 
-func getCheckInfo() (xml.Parent, error) {
-	fe, err := wz.GetFileCache().GetFile("Check.img.xml")
-	if err != nil {
-		return nil, err
-	}
-	ci, err := xml.Read(fe.Path())
-	if err != nil {
-		return nil, err
+// childIndex maps the name of each child of p to the child, resolving links as ChildByName would. It spares a linear
+// search of the several thousand quests for each quest read.
+func childIndex(p xml.Parent) map[string]xml.Noder {
+	results := make(map[string]xml.Noder, len(p.Children()))
+	for _, c := range p.Children() {
+		if _, ok := results[c.Name()]; ok {
+			continue
+		}
+		if _, ok := c.(*xml.UOLNode); !ok {
+			results[c.Name()] = c
+			continue
+		}
+		n, err := p.ChildByName(c.Name())
+		if err == nil {
+			results[c.Name()] = n
+		}
 	}
-	return ci, nil
+	return results
 }
 
-func getActInfo() (xml.Parent, error) {
-	fe, err := wz.GetFileCache().GetFile("Act.img.xml")
-	if err != nil {
-		return nil, err
-	}
-	ci, err := xml.Read(fe.Path())
-	if err != nil {
-		return nil, err
-	}
-	return ci, nil
+func getCheckInfo(t tenant.Model) (xml.Parent, error) {
+	return wz.GetFileCache(t.Id()).GetNode("Check.img.xml")
 }
 
-func getQuestInfo() (xml.Parent, error) {
-	fe, err := wz.GetFileCache().GetFile("QuestInfo.img.xml")
-	if err != nil {
-		return nil, err
-	}
-	fn, err := xml.Read(fe.Path())
-	if err != nil {
-		return nil, err
-	}
-	return fn, nil
+func getActInfo(t tenant.Model) (xml.Parent, error) {
+	return wz.GetFileCache(t.Id()).GetNode("Act.img.xml")
 }
 
-func createQuest(questId uint16, cn xml.Noder, ci xml.Parent, ai xml.Parent) (Model, error) {
-	modelBuilder := NewBuilder(questId)
+func getQuestInfo(t tenant.Model) (xml.Parent, error) {
+	return wz.GetFileCache(t.Id()).GetNode("QuestInfo.img.xml")
+}
 
-	rd, err := ci.ChildByName(strconv.Itoa(int(questId)))
-	if err != nil {
-		// most likely infoEx
-		return modelBuilder.Build(), nil
+// createQuest builds the quest identified by questId from its QuestInfo, Check and Act nodes, the latter two of which
+// are nil when absent. Problems with individual requirements or actions are reported as diagnostics and the rest of the
+// quest is still loaded. When the quest as a whole cannot be built, an error is returned and the reason is included in
+// the diagnostics.
+func createQuest(t tenant.Model, questId uint16, cn xml.Noder, rd xml.Noder, ad xml.Noder) (Model, []diagnostic.Model, error) {
+	modelBuilder := NewBuilder(questId)
+	diagnostics := make([]diagnostic.Model, 0)
+	fail := func(phase diagnostic.Phase, path string, err error) (Model, []diagnostic.Model, error) {
+		d := diagnostic.NewSkipped(questId, phase, path, err)
+		return modelBuilder.Build(), append(diagnostics, d), d
 	}
+	infoPath := fmt.Sprintf("QuestInfo.img/%d", questId)
 
 	qi, ok := cn.(xml.Parent)
 	if !ok {
-		return modelBuilder.Build(), errors.New("invalid xml structure")
+		return fail(diagnostic.PhaseInfo, infoPath, errors.New("invalid xml structure"))
 	}
+	diagnostics = append(diagnostics, unsupportedDiagnostics(questId, qi, infoPath, diagnostic.PhaseInfo, diagnostic.PhaseInfo)...)
 	name, err := xml.GetString(qi, "name")
 	if err != nil {
-		return modelBuilder.Build(), err
+		return fail(diagnostic.PhaseInfo, infoPath+"/name", err)
 	}
 	modelBuilder.SetName(name)
 	parent, err := xml.GetString(qi, "parent")
@@ -122,12 +149,97 @@ func createQuest(questId uint16, cn xml.Noder, ci xml.Parent, ai xml.Parent) (Mo
 	if err == nil {
 		modelBuilder.SetMedalItem(uint32(viewMedalItem))
 	}
+	area, err := xml.GetInteger(qi, "area")
+	if err == nil {
+		modelBuilder.SetArea(uint32(area))
+	}
+	order, err := xml.GetInteger(qi, "order")
+	if err == nil {
+		modelBuilder.SetOrder(uint32(order))
+	}
+	sortKey, err := xml.GetString(qi, "sortkey")
+	if err == nil {
+		if val, err := strconv.Atoi(sortKey); err == nil {
+			modelBuilder.SetSortKey(uint32(val))
+		}
+	}
+	questType, err := xml.GetString(qi, "type")
+	if err == nil {
+		modelBuilder.SetType(questType)
+	}
+	summary, err := xml.GetString(qi, "summary")
+	if err == nil {
+		modelBuilder.SetSummary(summary)
+	}
+	demandSummary, err := xml.GetString(qi, "demandSummary")
+	if err == nil {
+		modelBuilder.SetDemandSummary(demandSummary)
+	}
+	rewardSummary, err := xml.GetString(qi, "rewardSummary")
+	if err == nil {
+		modelBuilder.SetRewardSummary(rewardSummary)
+	}
+	selectedMob, err := xml.GetBoolean(qi, "selectedMob")
+	if err == nil {
+		modelBuilder.SetSelectedMob(selectedMob)
+	}
+	selectedSkillId, err := xml.GetInteger(qi, "selectedSkillID")
+	if err == nil {
+		modelBuilder.SetSelectedSkillId(uint32(selectedSkillId))
+	}
+	medalCategory, err := xml.GetInteger(qi, "medalCategory")
+	if err == nil {
+		modelBuilder.SetMedalCategory(uint32(medalCategory))
+	}
+	autoAccept, err := xml.GetBoolean(qi, "autoAccept")
+	if err == nil {
+		modelBuilder.SetAutoAccept(autoAccept)
+	}
+	timerUI, err := xml.GetString(qi, "timerUI")
+	if err == nil {
+		modelBuilder.SetTimerUI(timerUI)
+	}
+	showLayerTag, err := xml.GetString(qi, "showLayerTag")
+	if err == nil {
+		modelBuilder.SetShowLayerTag(showLayerTag)
+	}
+	oneShot, err := xml.GetBoolean(qi, "oneShot")
+	if err == nil {
+		modelBuilder.SetOneShot(oneShot)
+	}
+	dailyPlayTime, err := xml.GetInteger(qi, "dailyPlayTime")
+	if err == nil {
+		modelBuilder.SetDailyPlayTime(uint32(dailyPlayTime))
+	}
+	startDescription, err := xml.GetString(qi, "0")
+	if err == nil {
+		modelBuilder.SetStartDescription(startDescription)
+	}
+	progressDescription, err := xml.GetString(qi, "1")
+	if err == nil {
+		modelBuilder.SetProgressDescription(progressDescription)
+	}
+	completeDescription, err := xml.GetString(qi, "2")
+	if err == nil {
+		modelBuilder.SetCompleteDescription(completeDescription)
+	}
+
+	if rd == nil {
+		// most likely infoEx
+		addServiceRequirements(t, questId, modelBuilder)
+		return modelBuilder.Build(), diagnostics, nil
+	}
+	checkPath := fmt.Sprintf("Check.img/%d", questId)
+	if p, ok := rd.(xml.Parent); ok {
+		diagnostics = append(diagnostics, unsupportedDiagnostics(questId, p, checkPath, diagnostic.PhaseStartRequirement, diagnostic.PhaseCompleteRequirement)...)
+	}
 
 	// load starting requirements
-	srs, err := requirement.GetStarting(questId, rd)
+	srs, ds, err := requirement.GetStarting(t, questId, rd)
 	if err != nil {
-		return modelBuilder.Build(), err
+		return fail(diagnostic.PhaseStartRequirement, checkPath+"/0", err)
 	}
+	diagnostics = append(diagnostics, ds...)
 	for _, sr := range srs {
 		if sr.Type() == requirement.TypeInterval {
 			modelBuilder.SetRepeatable(true)
@@ -135,15 +247,35 @@ func createQuest(questId uint16, cn xml.Noder, ci xml.Parent, ai xml.Parent) (Mo
 			for _, rm := range sr.RelevantMobs() {
 				modelBuilder.AddRelevantMob(rm)
 			}
+		} else if sr.Script() != "" {
+			modelBuilder.AddScript(sr.Script())
+			modelBuilder.AddStartingAction(action.TypeScript, nil, action.RunScript(t, questId, sr.Script()))
+		} else if sr.Type() == requirement.TypeMinimumLevel {
+			modelBuilder.SetMinLevel(sr.Level())
+		} else if sr.Type() == requirement.TypeMaximumLevel {
+			modelBuilder.SetMaxLevel(sr.Level())
+		} else if sr.Type() == requirement.TypeJob {
+			modelBuilder.SetJobs(sr.Jobs())
+		} else if sr.Type() == requirement.TypeItem {
+			for _, it := range sr.Items() {
+				modelBuilder.AddStartItem(it.Id(), it.Count())
+			}
+		} else if sr.Type() == requirement.TypeStart {
+			modelBuilder.SetEventStart(sr.Date())
+			continue
+		} else if sr.Type() == requirement.TypeEndDate {
+			modelBuilder.SetEventEnd(sr.Date())
+			continue
 		}
 		modelBuilder.AddStartingRequirement(sr.Type(), sr.Check())
 	}
 
 	// load completion requirements
-	ers, err := requirement.GetEnding(questId, rd)
+	ers, ds, err := requirement.GetEnding(t, questId, rd)
 	if err != nil {
-		return modelBuilder.Build(), err
+		return fail(diagnostic.PhaseCompleteRequirement, checkPath+"/1", err)
 	}
+	diagnostics = append(diagnostics, ds...)
 	for _, er := range ers {
 		if er.Type() == requirement.TypeInterval {
 			modelBuilder.SetRepeatable(true)
@@ -151,30 +283,154 @@ func createQuest(questId uint16, cn xml.Noder, ci xml.Parent, ai xml.Parent) (Mo
 			for _, rm := range er.RelevantMobs() {
 				modelBuilder.AddRelevantMob(rm)
 			}
+			modelBuilder.AddKills(er.Mobs()...)
+		} else if er.Script() != "" {
+			modelBuilder.AddScript(er.Script())
+			modelBuilder.AddCompletionAction(action.TypeScript, nil, action.RunScript(t, questId, er.Script()))
+		} else if er.Type() == requirement.TypeItem {
+			for _, it := range er.Items() {
+				modelBuilder.AddCompleteItem(it.Id(), it.Count())
+			}
 		}
 		modelBuilder.AddCompletionRequirement(er.Type(), er.Check())
 	}
+	addServiceRequirements(t, questId, modelBuilder)
 
-	ad, err := ai.ChildByName(strconv.Itoa(int(questId)))
-	if ad == nil || err != nil {
-		return modelBuilder.Build(), nil
+	if ad == nil {
+		return modelBuilder.Build(), diagnostics, nil
+	}
+	actPath := fmt.Sprintf("Act.img/%d", questId)
+	if p, ok := ad.(xml.Parent); ok {
+		diagnostics = append(diagnostics, unsupportedDiagnostics(questId, p, actPath, diagnostic.PhaseStartAction, diagnostic.PhaseCompleteAction)...)
 	}
 
-	sas, err := action.GetStarting(questId, ad)
+	sas, ds, err := action.GetStarting(t, questId, ad)
 	if err != nil {
-		return modelBuilder.Build(), err
+		return fail(diagnostic.PhaseStartAction, actPath+"/0", err)
 	}
+	diagnostics = append(diagnostics, ds...)
 	for _, sa := range sas {
+		for _, it := range sa.Items() {
+			modelBuilder.AddStartActionItem(it.Id(), it.Count())
+		}
+		if sa.ChangesJob() {
+			modelBuilder.SetJobAdvancement(true)
+		}
 		modelBuilder.AddStartingAction(sa.Type(), sa.Check(), sa.Run())
 	}
 
-	cas, err := action.GetEnding(questId, ad)
+	cas, ds, err := action.GetEnding(t, questId, ad)
 	if err != nil {
-		return modelBuilder.Build(), err
+		return fail(diagnostic.PhaseCompleteAction, actPath+"/1", err)
 	}
+	diagnostics = append(diagnostics, ds...)
 	for _, sa := range cas {
+		for _, it := range sa.Items() {
+			modelBuilder.AddActionItem(it.Id(), it.Count())
+		}
+		if sa.ChangesJob() {
+			modelBuilder.SetJobAdvancement(true)
+		}
 		modelBuilder.AddCompletionAction(sa.Type(), sa.Check(), sa.Run())
 	}
 
-	return modelBuilder.Build(), nil
+	sc, err := conversation.GetStarting(ad)
+	if err != nil {
+		diagnostics = append(diagnostics, diagnostic.NewModel(questId, diagnostic.PhaseStartAction, actPath+"/0/stop", err))
+	}
+	modelBuilder.SetStartConversation(sc)
+	if sc.Gated() {
+		modelBuilder.AddStartingRequirement(requirement.TypeConversation, conversationCheck(questId, conversation.PhaseStart))
+	}
+
+	cc, err := conversation.GetEnding(ad)
+	if err != nil {
+		diagnostics = append(diagnostics, diagnostic.NewModel(questId, diagnostic.PhaseCompleteAction, actPath+"/1/stop", err))
+	}
+	modelBuilder.SetCompleteConversation(cc)
+	if cc.Gated() {
+		modelBuilder.AddCompletionRequirement(requirement.TypeConversation, conversationCheck(questId, conversation.PhaseComplete))
+	}
+
+	return modelBuilder.Build(), diagnostics, nil
+}
+
+// unsupportedDiagnostics reports each node beneath p which the reader skipped, as its type was not understood. Nodes
+// beneath the "1" child are attributed to the completion phase, and all others to the starting phase.
+func unsupportedDiagnostics(questId uint16, p xml.Parent, path string, starting diagnostic.Phase, completing diagnostic.Phase) []diagnostic.Model {
+	results := make([]diagnostic.Model, 0)
+	xml.WalkUnsupported(p, func(rel string, n *xml.UnsupportedNode) {
+		phase := starting
+		if strings.HasPrefix(rel, "1/") {
+			phase = completing
+		}
+		results = append(results, diagnostic.NewModel(questId, phase, path+"/"+rel, errors.New(fmt.Sprintf("unsupported node type %s", n.Kind()))))
+	})
+	return results
+}
+
+// addServiceRequirements adds the starting requirements imposed by the service, rather than the WZ data, which apply to
+// every quest.
+func addServiceRequirements(t tenant.Model, questId uint16, modelBuilder *ModelBuilder) {
+	modelBuilder.AddStartingRequirement(requirement.TypeWorldAvailability, worldAvailabilityCheck(t, questId))
+	modelBuilder.AddStartingRequirement(requirement.TypeEventWindow, eventWindowCheck(t, modelBuilder.EventWindow()))
+	modelBuilder.AddStartingRequirement(requirement.TypeForfeitCooldown, forfeitCooldownCheck(t, questId))
+}
+
+// eventWindowCheck requires the quest to be started within its event window, or that set by an operator in its place.
+func eventWindowCheck(t tenant.Model, base event.Window) requirement.CheckFunc {
+	return func(_ logrus.FieldLogger, _ opentracing.Span, _ *gorm.DB) func(_ uint32, _ uint32) bool {
+		return func(_ uint32, _ uint32) bool {
+			return event.GetRegistry().Resolve(t.Id(), base).Open(time.Now())
+		}
+	}
+}
+
+// worldAvailabilityCheck requires the quest to be enabled in the world the character resides in.
+func worldAvailabilityCheck(t tenant.Model, questId uint16) requirement.CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			if !world.GetRegistry().IsConfigured(t.Id(), questId) {
+				return true
+			}
+			c, err := character.GetById(l, span, t)(characterId)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to retrieve character %d for world availability of quest %d.", characterId, questId)
+				return false
+			}
+			return world.GetRegistry().IsAvailable(t.Id(), c.WorldId(), questId)
+		}
+	}
+}
+
+// forfeitCooldownCheck requires the cooldown following the character forfeiting the quest to have elapsed.
+func forfeitCooldownCheck(t tenant.Model, questId uint16) requirement.CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			cooldown := forfeit.GetRegistry().Cooldown(t.Id(), questId)
+			if cooldown <= 0 {
+				return true
+			}
+			cq, err := characterquest.GetById(l, span, db)(characterId, questId)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to retrieve quest %d information for character %d. Assuming check fails.", questId, characterId)
+				return false
+			}
+			return !time.Now().Before(availableAt(t, cq))
+		}
+	}
+}
+
+// conversationCheck requires the character to have passed the quiz or confirmation held in the phase conversation.
+func conversationCheck(questId uint16, phase string) requirement.CheckFunc {
+	return func(l logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			ok, err := conversation.Passed(l, db)(characterId, questId, phase)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to retrieve %s conversation outcome of quest %d for character %d.", phase, questId, characterId)
+				return false
+			}
+			return ok
+		}
+	}
 }
@@ -0,0 +1,70 @@
+package quest
+
+import (
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/quest/requirement"
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadStrict(t *testing.T) {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	tm := tenant.NewModel("strict-test", wzDir, "", "")
+	wz.GetFileCache(tm.Id()).Init(wzDir)
+
+	err := GetCache(tm).Init(SetStrict(true))
+	if err != nil {
+		t.Fatalf("strict load of the bundled data failed: %v", err)
+	}
+	if ds := GetCache(tm).GetDiagnostics(); len(ds) > 0 {
+		t.Errorf("strict load reported %d diagnostics, first %v", len(ds), ds[0])
+	}
+}
+
+func TestServiceRequirements(t *testing.T) {
+	tm := loadQuests(t)
+	// 9800 has no Check.img entry, 1016 does.
+	for _, id := range []uint32{9800, 1016} {
+		q, err := GetById(nil, tm)(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rt := range []requirement.Type{requirement.TypeWorldAvailability, requirement.TypeEventWindow, requirement.TypeForfeitCooldown} {
+			if _, ok := q.startRequirements[rt]; !ok {
+				t.Errorf("quest %d is missing starting requirement %s", id, rt)
+			}
+		}
+	}
+}
+
+func TestUnsupportedNodesReported(t *testing.T) {
+	dir := copyQuestData(t)
+	rewrite(t, filepath.Join(dir, "Check.img.xml"), `<imgdir name="4513">
+        <imgdir name="0">`, `<imgdir name="4513">
+        <imgdir name="0">
+            <blob name="unknown"/>`)
+	tm := tenant.NewModel("unsupported-test", dir, "", "")
+	wz.GetFileCache(tm.Id()).Init(dir)
+	err := GetCache(tm).Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = GetById(nil, tm)(4513); err != nil {
+		t.Fatalf("quest 4513 was not loaded: %v", err)
+	}
+	var found bool
+	for _, d := range GetCache(tm).GetDiagnostics() {
+		if d.QuestId() == 4513 && d.Path() == "Check.img/4513/0/unknown" && d.Phase() == diagnostic.PhaseStartRequirement {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("skipped node of quest 4513 was not reported")
+	}
+}
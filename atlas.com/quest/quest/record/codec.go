@@ -0,0 +1,54 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// countWidth is the number of digits the client allots to each kill count.
+	countWidth = 3
+	// MaxCount is the largest kill count the client can display. Larger counts are encoded as this.
+	MaxCount = 999
+)
+
+// Encode produces the client quest record, the zero padded kill count of each monster in turn followed by the info.
+func Encode(m Model) string {
+	sb := strings.Builder{}
+	for _, k := range m.kills {
+		c := k.count
+		if c > MaxCount {
+			c = MaxCount
+		}
+		sb.WriteString(fmt.Sprintf("%0*d", countWidth, c))
+	}
+	sb.WriteString(m.info)
+	return sb.String()
+}
+
+// Decode reads a client quest record for a quest requiring the monsters, given in the order Check.img lists them. An
+// empty record is one in which no monsters have been killed.
+func Decode(mobIds []uint32, value string) (Model, error) {
+	kills := make([]Kill, 0, len(mobIds))
+	if value == "" {
+		for _, id := range mobIds {
+			kills = append(kills, Kill{mobId: id})
+		}
+		return Model{kills: kills}, nil
+	}
+
+	if len(value) < len(mobIds)*countWidth {
+		return Model{}, errors.New(fmt.Sprintf("record [%s] is too short to hold %d kill counts", value, len(mobIds)))
+	}
+	for i, id := range mobIds {
+		digits := value[i*countWidth : (i+1)*countWidth]
+		c, err := strconv.ParseUint(digits, 10, 32)
+		if err != nil {
+			return Model{}, errors.New(fmt.Sprintf("record [%s] holds invalid kill count [%s]", value, digits))
+		}
+		kills = append(kills, Kill{mobId: id, count: uint32(c)})
+	}
+	return Model{kills: kills, info: value[len(mobIds)*countWidth:]}, nil
+}
@@ -0,0 +1,63 @@
+package record
+
+import "testing"
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		model Model
+		want  string
+	}{
+		{"empty", NewModel(nil, ""), ""},
+		{"padded", NewModel([]Kill{NewKill(100100, 7), NewKill(100101, 42), NewKill(120100, 300)}, ""), "007042300"},
+		{"clamped", NewModel([]Kill{NewKill(100100, 1500)}, ""), "999"},
+		{"info", NewModel([]Kill{NewKill(100100, 1)}, "abc"), "001abc"},
+		{"info only", NewModel(nil, "s=1;m=2"), "s=1;m=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.model); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	mobIds := []uint32{100100, 100101, 120100}
+
+	m, err := Decode(mobIds, "007042300xyz")
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	for i, want := range []uint32{7, 42, 300} {
+		k := m.Kills()[i]
+		if k.MobId() != mobIds[i] || k.Count() != want {
+			t.Errorf("kill %d = (%d, %d), want (%d, %d)", i, k.MobId(), k.Count(), mobIds[i], want)
+		}
+	}
+	if m.Info() != "xyz" {
+		t.Errorf("Info() = %q, want %q", m.Info(), "xyz")
+	}
+	if m.Count(100101) != 42 || m.Count(9999999) != 0 {
+		t.Errorf("Count() = (%d, %d), want (42, 0)", m.Count(100101), m.Count(9999999))
+	}
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	m, err := Decode([]uint32{100100, 100101}, "")
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(m.Kills()) != 2 || m.Count(100100) != 0 || m.Count(100101) != 0 {
+		t.Errorf("Decode() of empty record = %v, want zero kills of both monsters", m.Kills())
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, value := range []string{"0070", "00a042", "-01042"} {
+		if _, err := Decode([]uint32{100100, 100101}, value); err == nil {
+			t.Errorf("Decode(%q) succeeded, want error", value)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package record
+
+// Kill is the number of a monster killed towards a quest.
+type Kill struct {
+	mobId uint32
+	count uint32
+}
+
+func NewKill(mobId uint32, count uint32) Kill {
+	return Kill{mobId: mobId, count: count}
+}
+
+func (k Kill) MobId() uint32 {
+	return k.mobId
+}
+
+func (k Kill) Count() uint32 {
+	return k.count
+}
+
+// Model is the structured form of a client quest record. It holds the kill count of each monster the quest requires,
+// in the order Check.img lists them, followed by any free-form info.
+type Model struct {
+	kills []Kill
+	info  string
+}
+
+func NewModel(kills []Kill, info string) Model {
+	return Model{kills: kills, info: info}
+}
+
+func (m Model) Kills() []Kill {
+	return m.kills
+}
+
+func (m Model) Info() string {
+	return m.info
+}
+
+// Count is the number of the monster killed. A monster the record does not track has a count of zero.
+func (m Model) Count(mobId uint32) uint32 {
+	for _, k := range m.kills {
+		if k.mobId == mobId {
+			return k.count
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package quest
+
+import (
+	"atlas-quest/tenant"
+	"atlas-quest/wz"
+	"os"
+	"testing"
+)
+
+const wzDir = "../../../wz"
+
+func loadQuests(t *testing.T) tenant.Model {
+	if _, err := os.Stat(wzDir); err != nil {
+		t.Skipf("%s is not available.", wzDir)
+	}
+	tm := tenant.NewModel("record-test", wzDir, "", "")
+	wz.GetFileCache(tm.Id()).Init(wzDir)
+	err := GetCache(tm).Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestRecordOrder(t *testing.T) {
+	tm := loadQuests(t)
+	q, err := GetById(nil, tm)(1016)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []uint32{100100, 100101, 120100}
+	if len(q.Kills()) != len(want) {
+		t.Fatalf("quest 1016 requires %d monsters, want %d", len(q.Kills()), len(want))
+	}
+	for i, k := range q.Kills() {
+		if k.Id() != want[i] {
+			t.Errorf("monster %d of quest 1016 = %d, want %d", i, k.Id(), want[i])
+		}
+	}
+
+	value := EncodeRecord(q, map[uint32]uint32{100101: 3, 120100: 12}, "")
+	if value != "000003012" {
+		t.Errorf("EncodeRecord() = %q, want %q", value, "000003012")
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	tm := loadQuests(t)
+
+	tested := 0
+	for _, q := range GetCache(tm).GetQuests() {
+		if len(q.Kills()) == 0 {
+			continue
+		}
+		kills := make(map[uint32]uint32)
+		for i, k := range q.Kills() {
+			kills[k.Id()] = (k.Count() + uint32(i)*7) % 1000
+		}
+
+		for _, info := range []string{"", "info"} {
+			value := EncodeRecord(q, kills, info)
+			if len(value) != len(q.Kills())*3+len(info) {
+				t.Errorf("quest %d record %q has unexpected length", q.Id(), value)
+				continue
+			}
+			m, err := DecodeRecord(q, value)
+			if err != nil {
+				t.Errorf("quest %d record %q: %v", q.Id(), value, err)
+				continue
+			}
+			for i, k := range m.Kills() {
+				if k.MobId() != q.Kills()[i].Id() || k.Count() != kills[k.MobId()] {
+					t.Errorf("quest %d kill %d = (%d, %d), want (%d, %d)", q.Id(), i, k.MobId(), k.Count(), q.Kills()[i].Id(), kills[k.MobId()])
+				}
+			}
+			if m.Info() != info {
+				t.Errorf("quest %d info = %q, want %q", q.Id(), m.Info(), info)
+			}
+		}
+		tested++
+	}
+	if tested == 0 {
+		t.Error("no quests requiring monsters were loaded")
+	}
+}
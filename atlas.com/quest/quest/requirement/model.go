@@ -4,6 +4,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"time"
 )
 
 const (
@@ -17,8 +18,8 @@ const (
 	TypeNPC                  = "NPC"
 	TypeFieldEnter           = "FIELD_ENTER"
 	TypeInterval             = "INTERVAL"
-	TypeStartScript          = "SCRIPT"
-	TypeEndScript            = "SCRIPT"
+	TypeStartScript          = "START_SCRIPT"
+	TypeEndScript            = "END_SCRIPT"
 	TypePet                  = "PET"
 	TypePetTamenessMinimum   = "MIN_PET_TAMENESS"
 	TypeMonsterBook          = "MONSTER_BOOK"
@@ -47,16 +48,54 @@ const (
 	TypePetRecallLimit       = "PET_RECALL_LIMIT"
 	TypePetAutoSpeakingLimit = "PET_AUTO_SPEAKING_LIMIT"
 	TypeTamingMobLevelMin    = "TAMING_MOB_LEVEL_MIN"
+	TypeConversation         = "CONVERSATION"
+	TypeWorldAvailability    = "WORLD_AVAILABILITY"
+	TypeEventWindow          = "EVENT_WINDOW"
+	TypeForfeitCooldown      = "FORFEIT_COOLDOWN"
 )
 
 type Type string
 
 type CheckFunc func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32) bool
 
+// Item is an item named by an item requirement, along with the quantity required. Entries without a quantity have a zero
+// count.
+type Item struct {
+	id    uint32
+	count int32
+}
+
+func (i Item) Id() uint32 {
+	return i.id
+}
+
+func (i Item) Count() int32 {
+	return i.count
+}
+
+// Mob is a monster a mob requirement calls for to be hunted, along with the number to be killed.
+type Mob struct {
+	id    uint32
+	count uint32
+}
+
+func (m Mob) Id() uint32 {
+	return m.id
+}
+
+func (m Mob) Count() uint32 {
+	return m.count
+}
+
 type Model struct {
-	typeString   Type
-	relevantMobs []uint32
-	check        CheckFunc
+	typeString Type
+	mobs       []Mob
+	script     string
+	date       time.Time
+	items      []Item
+	jobs       []uint16
+	level      byte
+	check      CheckFunc
 }
 
 func (m Model) Type() Type {
@@ -64,7 +103,41 @@ func (m Model) Type() Type {
 }
 
 func (m Model) RelevantMobs() []uint32 {
-	return m.relevantMobs
+	results := make([]uint32, 0, len(m.mobs))
+	for _, mob := range m.mobs {
+		results = append(results, mob.Id())
+	}
+	return results
+}
+
+// Mobs lists, in the order the client tracks them, the monsters a mob requirement calls for.
+func (m Model) Mobs() []Mob {
+	return m.mobs
+}
+
+// Script is the name of the script invoked by a start or end script requirement.
+func (m Model) Script() string {
+	return m.script
+}
+
+// Items lists the items an item requirement calls for.
+func (m Model) Items() []Item {
+	return m.items
+}
+
+// Jobs lists the jobs a job requirement permits.
+func (m Model) Jobs() []uint16 {
+	return m.jobs
+}
+
+// Level is the bound set by a minimum or maximum level requirement.
+func (m Model) Level() byte {
+	return m.level
+}
+
+// Date is the moment named by a start or end requirement.
+func (m Model) Date() time.Time {
+	return m.date
 }
 
 func (m Model) Check() CheckFunc {
@@ -2,9 +2,18 @@ package requirement
 
 import (
 	"atlas-quest/character"
+	"atlas-quest/character/inventory"
+	"atlas-quest/character/pet"
 	"atlas-quest/character/quest"
+	"atlas-quest/monsterbook"
+	"atlas-quest/partyquest"
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/quest/event"
+	"atlas-quest/quest/script"
+	"atlas-quest/tenant"
 	"atlas-quest/xml"
 	"errors"
+	"fmt"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -12,54 +21,99 @@ import (
 	"time"
 )
 
-func get(questId uint16, root xml.Noder, nodeName string) ([]Model, error) {
+func get(t tenant.Model, questId uint16, root xml.Noder, nodeName string, phase diagnostic.Phase) ([]Model, []diagnostic.Model, error) {
 	questData, ok := root.(xml.Parent)
 	if !ok {
-		return nil, errors.New("invalid xml structure")
+		return nil, nil, errors.New("invalid xml structure")
 	}
 
 	requirementsRoot, err := questData.ChildByName(nodeName)
 	if err != nil {
-		return nil, errors.New("invalid xml structure")
+		return nil, nil, errors.New("invalid xml structure")
 	}
 
 	rootAsParent, ok := requirementsRoot.(xml.Parent)
 	if !ok {
-		return nil, errors.New("invalid xml structure")
+		return nil, nil, errors.New("invalid xml structure")
 	}
 
 	results := make([]Model, 0)
+	diagnostics := make([]diagnostic.Model, 0)
 	for _, req := range rootAsParent.Children() {
+		path := fmt.Sprintf("Check.img/%d/%s/%s", questId, nodeName, req.Name())
 		reqType, err := getByWZName(req.Name())
 		if err != nil {
-			return nil, err
+			diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+			continue
 		}
 
 		m := Model{typeString: reqType}
 		if reqType == TypeMob {
-			rms, err := getRelevantMobs(req)
+			mobs, err := getMobs(req)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			m.mobs = mobs
+		} else if reqType == TypeStartScript || reqType == TypeEndScript {
+			name, err := xml.StringFromStringNode(req)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			m.script = name
+		} else if reqType == TypeItem {
+			items, err := getItems(req)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			m.items = items
+		} else if reqType == TypeJob {
+			ids, err := getJobIds(req)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			m.jobs = ids
+		} else if reqType == TypeMinimumLevel || reqType == TypeMaximumLevel {
+			val, err := xml.IntFromIntegerNode(req)
 			if err != nil {
-				return nil, err
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
 			}
-			m.relevantMobs = rms
+			m.level = byte(val)
+		} else if reqType == TypeStart || reqType == TypeEndDate {
+			val, err := xml.StringFromStringNode(req)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			date, err := event.ParseDate(val)
+			if err != nil {
+				diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+				continue
+			}
+			m.date = date
 		}
-		check, err := getCheckProducer(questId, reqType, req)()
+		check, err := getCheckProducer(t, questId, reqType, req)()
 		if err != nil {
-			return nil, err
+			diagnostics = append(diagnostics, diagnostic.NewModel(questId, phase, path, err))
+			continue
 		}
 		m.check = check
 		results = append(results, m)
 	}
-	return results, nil
+	return results, diagnostics, nil
 }
 
-func getRelevantMobs(req xml.Noder) ([]uint32, error) {
+func getMobs(req xml.Noder) ([]Mob, error) {
 	reqAsParent, ok := req.(xml.Parent)
 	if !ok {
 		return nil, errors.New("invalid xml structure")
 	}
 
-	results := make([]uint32, 0)
+	results := make([]Mob, 0)
 	for _, md := range reqAsParent.Children() {
 		mob, ok := md.(xml.Parent)
 		if !ok {
@@ -69,23 +123,61 @@ func getRelevantMobs(req xml.Noder) ([]uint32, error) {
 		if err != nil {
 			return nil, err
 		}
-		results = append(results, uint32(mid))
+		results = append(results, Mob{id: uint32(mid), count: uint32(xml.GetIntegerWithDefault(mob, "count", 0))})
+	}
+	return results, nil
+}
+
+func getItems(req xml.Noder) ([]Item, error) {
+	reqAsParent, ok := req.(xml.Parent)
+	if !ok {
+		return nil, errors.New("invalid xml structure")
+	}
+
+	results := make([]Item, 0)
+	for _, id := range reqAsParent.Children() {
+		item, ok := id.(xml.Parent)
+		if !ok {
+			return nil, errors.New("invalid xml structure")
+		}
+		iid, err := xml.GetInteger(item, "id")
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, Item{id: uint32(iid), count: xml.GetIntegerWithDefault(item, "count", 0)})
+	}
+	return results, nil
+}
+
+func getJobIds(req xml.Noder) ([]uint16, error) {
+	reqAsParent, ok := req.(xml.Parent)
+	if !ok {
+		return nil, errors.New("invalid xml structure")
+	}
+
+	results := make([]uint16, 0)
+	for _, jd := range reqAsParent.Children() {
+		id, err := xml.IntFromIntegerNode(jd)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, uint16(id))
 	}
 	return results, nil
 }
 
 type checkProducer func() (CheckFunc, error)
 
-func getCheckProducer(questId uint16, rt Type, sr xml.Noder) checkProducer {
+func getCheckProducer(t tenant.Model, questId uint16, rt Type, sr xml.Noder) checkProducer {
 	switch rt {
 	case TypeEndDate:
 		return endDateRequirement(sr)
 	case TypeJob:
-		return jobRequirement(sr)
+		return jobRequirement(t, sr)
 	case TypeQuest:
 		return otherQuestRequirement(sr)
 	case TypeFieldEnter:
-		return fieldEnterRequirement(sr)
+		return fieldEnterRequirement(t, sr)
 	case TypeInfoNumber:
 		return infoNumberRequirement(sr)
 	case TypeInfoEx:
@@ -95,15 +187,15 @@ func getCheckProducer(questId uint16, rt Type, sr xml.Noder) checkProducer {
 	case TypeQuestComplete:
 		return questCompleteRequirement(sr)
 	case TypeItem:
-		return itemRequirement(sr)
+		return itemRequirement(t, sr)
 	case TypeMaximumLevel:
-		return maxLevelRequirement(sr)
+		return maxLevelRequirement(t, sr)
 	case TypeMoney:
-		return mesoRequirement(sr)
+		return mesoRequirement(t, sr)
 	case TypeMinimumLevel:
-		return minLevelRequirement(sr)
+		return minLevelRequirement(t, sr)
 	case TypePetTamenessMinimum:
-		return petTamenessMinimumRequirement(sr)
+		return petTamenessMinimumRequirement(t, sr)
 	case TypeMob:
 		return monsterRequirement(questId, sr)
 	case TypeMonsterBook:
@@ -111,21 +203,21 @@ func getCheckProducer(questId uint16, rt Type, sr xml.Noder) checkProducer {
 	case TypeNPC:
 		return npcRequirement(sr)
 	case TypePet:
-		return petRequirement(sr)
+		return petRequirement(t, sr)
 	case TypeBuff:
-		return buffRequirement(sr)
+		return buffRequirement(t, sr)
 	case TypeExceptBuff:
-		return exceptBuffRequirement(sr)
+		return exceptBuffRequirement(t, sr)
 	case TypeStartScript:
-		return scriptRequirement(sr)
-	//case TypeEndScript:
-	//	return scriptRequirement(sr)
+		return scriptRequirement(t, questId, sr)
+	case TypeEndScript:
+		return scriptRequirement(t, questId, sr)
 	case TypeEquipAllNeed:
-		return validRequirementProducer(invalidCheck)
+		return equipAllNeedRequirement(t, sr)
 	case TypeEquipSelectNeed:
-		return validRequirementProducer(invalidCheck)
+		return equipSelectNeedRequirement(t, sr)
 	case TypeSkill:
-		return skillRequirement(sr)
+		return skillRequirement(t, sr)
 	case TypeInfo:
 		return validRequirementProducer(invalidCheck)
 	case TypeMonsterBookCard:
@@ -137,27 +229,27 @@ func getCheckProducer(questId uint16, rt Type, sr xml.Noder) checkProducer {
 	case TypeDayByDay:
 		return dayByDayRequirement(sr)
 	case TypeWorldMin:
-		return worldMinRequirement(sr)
+		return worldMinRequirement(t, sr)
 	case TypeWorldMax:
-		return worldMaxRequirement(sr)
+		return worldMaxRequirement(t, sr)
 	case TypeMorph:
-		return morphRequirement(sr)
+		return morphRequirement(t, sr)
 	case TypePopularity:
-		return popularityRequirement(sr)
+		return popularityRequirement(t, sr)
 	case TypeEndMeso:
 		return endMesoRequirement(sr)
 	case TypeLevel:
-		return levelRequirement(sr)
+		return levelRequirement(t, sr)
 	case TypePartyQuestS:
-		return partyQuestSRequirement(sr)
+		return partyQuestSRequirement(t, sr)
 	case TypeUserInteract:
 		return userInteractRequirement(sr)
 	case TypePetRecallLimit:
-		return petRecallLimitRequirement(sr)
+		return petRecallLimitRequirement(t, sr)
 	case TypePetAutoSpeakingLimit:
-		return petAutoSpeakingLimitRequirement(sr)
+		return petAutoSpeakingLimitRequirement(t, sr)
 	case TypeTamingMobLevelMin:
-		return tamingMobLevelMinRequirement(sr)
+		return tamingMobLevelMinRequirement(t, sr)
 	}
 	return errorCheckProducer(errors.New("requirement type not found"))
 }
@@ -186,19 +278,49 @@ func validRequirementProducer(cf CheckFunc) checkProducer {
 	}
 }
 
-func tamingMobLevelMinRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func tamingMobLevelMinRequirement(t tenant.Model, sr xml.Noder) checkProducer {
+	val, err := xml.IntFromIntegerNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkMinMountLevel(t, byte(val)))
 }
 
-func petAutoSpeakingLimitRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func checkMinMountLevel(t tenant.Model, level byte) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return character.IsMinimalMountLevel(l, span, t)(characterId, level)
+		}
+	}
 }
 
-func petRecallLimitRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+// petAutoSpeakingLimitRequirement limits the quest to pets which have yet to learn to speak by themselves.
+func petAutoSpeakingLimitRequirement(t tenant.Model, sr xml.Noder) checkProducer {
+	return petSkillLimitRequirement(t, sr, pet.SkillAutoSpeaking)
+}
+
+// petRecallLimitRequirement limits the quest to pets which have yet to learn to be recalled.
+func petRecallLimitRequirement(t tenant.Model, sr xml.Noder) checkProducer {
+	return petSkillLimitRequirement(t, sr, pet.SkillRecall)
+}
+
+func petSkillLimitRequirement(t tenant.Model, sr xml.Noder, skill uint16) checkProducer {
+	val, err := xml.IntFromIntegerNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	if val == 0 {
+		return validRequirementProducer(validCheck)
+	}
+	return validRequirementProducer(checkPetWithoutSkill(t, skill))
+}
+
+func checkPetWithoutSkill(t tenant.Model, skill uint16) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return character.HasPetWithoutSkill(l, span, t)(characterId, skill)
+		}
+	}
 }
 
 func userInteractRequirement(_ xml.Noder) checkProducer {
@@ -206,23 +328,39 @@ func userInteractRequirement(_ xml.Noder) checkProducer {
 	return validRequirementProducer(validCheck)
 }
 
-func partyQuestSRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func partyQuestSRequirement(t tenant.Model, sr xml.Noder) checkProducer {
+	val, err := xml.IntFromIntegerNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkPartyQuestS(t, uint32(val)))
 }
 
-func levelRequirement(sr xml.Noder) checkProducer {
+func checkPartyQuestS(t tenant.Model, count uint32) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			ranked, err := partyquest.CountRank(l, span, db, t)(characterId, partyquest.RankS)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to retrieve party quest ranks for character %d. Assuming check fails.", characterId)
+				return false
+			}
+			return ranked >= count
+		}
+	}
+}
+
+func levelRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkLevel(byte(val)))
+	return validRequirementProducer(checkLevel(t, byte(val)))
 }
 
-func checkLevel(level byte) CheckFunc {
+func checkLevel(t tenant.Model, level byte) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.IsLevel(l, span)(characterId, level)
+			return character.IsLevel(l, span, t)(characterId, level)
 		}
 	}
 }
@@ -232,46 +370,68 @@ func endMesoRequirement(_ xml.Noder) checkProducer {
 	return validRequirementProducer(validCheck)
 }
 
-func popularityRequirement(sr xml.Noder) checkProducer {
+func popularityRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkPopularity(int16(val)))
+	return validRequirementProducer(checkPopularity(t, int16(val)))
 }
 
-func checkPopularity(pop int16) CheckFunc {
+func checkPopularity(t tenant.Model, pop int16) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.IsPopularityLevel(l, span)(characterId, pop)
+			return character.IsPopularityLevel(l, span, t)(characterId, pop)
 		}
 	}
 }
 
-func morphRequirement(sr xml.Noder) checkProducer {
+func morphRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkMorph(uint32(val)))
+	return validRequirementProducer(checkMorph(t, uint32(val)))
 }
 
-func checkMorph(morph uint32) CheckFunc {
+func checkMorph(t tenant.Model, morph uint32) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.IsMorphed(l, span)(characterId, morph)
+			return character.IsMorphed(l, span, t)(characterId, morph)
 		}
 	}
 }
 
-func worldMaxRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func worldMaxRequirement(t tenant.Model, sr xml.Noder) checkProducer {
+	val, err := xml.IntFromStringNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkMaxWorld(t, byte(val)))
 }
 
-func worldMinRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func checkMaxWorld(t tenant.Model, worldId byte) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return character.IsMaximalWorld(l, span, t)(characterId, worldId)
+		}
+	}
+}
+
+func worldMinRequirement(t tenant.Model, sr xml.Noder) checkProducer {
+	val, err := xml.IntFromStringNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkMinWorld(t, byte(val)))
+}
+
+func checkMinWorld(t tenant.Model, worldId byte) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return character.IsMinimalWorld(l, span, t)(characterId, worldId)
+		}
+	}
 }
 
 func dayByDayRequirement(_ xml.Noder) checkProducer {
@@ -279,9 +439,24 @@ func dayByDayRequirement(_ xml.Noder) checkProducer {
 	return validRequirementProducer(validCheck)
 }
 
-func startRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func startRequirement(sr xml.Noder) checkProducer {
+	val, err := xml.StringFromStringNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	startDate, err := event.ParseDate(val)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkStarted(startDate))
+}
+
+func checkStarted(startDate time.Time) CheckFunc {
+	return func(_ logrus.FieldLogger, _ opentracing.Span, _ *gorm.DB) func(_ uint32, _ uint32) bool {
+		return func(_ uint32, _ uint32) bool {
+			return !time.Now().Before(startDate)
+		}
+	}
 }
 
 func normalAutoStartRequirement(_ xml.Noder) checkProducer {
@@ -316,27 +491,25 @@ func monsterBookCardRequirement(r xml.Noder) checkProducer {
 }
 
 func checkMinMonsterBookCard(mins map[uint32]uint32) CheckFunc {
-	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+	return func(l logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			mb, err := character.GetMonsterBook(l, span)(characterId)
+			mb, err := monsterbook.GetBook(l, db)(characterId)
 			if err != nil {
 				l.WithError(err).Errorf("Unable to retrieve monster book for character %d. Assuming check fails.", characterId)
 				return false
 			}
 			for id, qty := range mins {
-				if val, ok := mb[id]; ok {
-					if val >= qty {
-						return true
-					}
+				if mb[id] < qty {
+					return false
 				}
 			}
-			return false
+			return true
 		}
 	}
 }
 
-func skillRequirement(r xml.Noder) checkProducer {
-	skills := make(map[uint32]uint32)
+func skillRequirement(t tenant.Model, r xml.Noder) checkProducer {
+	skills := make(map[uint32]bool)
 	srs, ok := r.(xml.Parent)
 	if !ok {
 		return errorCheckProducer(errors.New("invalid xml structure"))
@@ -353,57 +526,68 @@ func skillRequirement(r xml.Noder) checkProducer {
 			continue
 		}
 		acquire := xml.GetIntegerWithDefault(sd, "acquire", 0)
-		skills[uint32(id)] = uint32(acquire)
+		skills[uint32(id)] = acquire != 0
 	}
-	return validRequirementProducer(checkSkills(skills))
+	return validRequirementProducer(checkSkills(t, skills))
 }
 
-func checkSkills(skills map[uint32]uint32) CheckFunc {
+func checkSkills(t tenant.Model, skills map[uint32]bool) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.HasSkill(l, span)(characterId, skills)
+			return character.HasSkill(l, span, t)(characterId, skills)
 		}
 	}
 }
 
-func scriptRequirement(_ xml.Noder) checkProducer {
-	//TODO identify what this is supposed to be doing
-	return validRequirementProducer(validCheck)
+func scriptRequirement(t tenant.Model, questId uint16, sr xml.Noder) checkProducer {
+	name, err := xml.StringFromStringNode(sr)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkScript(t, questId, name))
+}
+
+func checkScript(t tenant.Model, questId uint16, name string) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32) bool {
+		return func(characterId uint32, npcId uint32) bool {
+			return script.Run(l, span, db, t)(script.NewContext(name, questId, characterId, npcId, 0))
+		}
+	}
 }
 
-func exceptBuffRequirement(sr xml.Noder) checkProducer {
+func exceptBuffRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromStringNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkBuffExcept(val * -1))
+	return validRequirementProducer(checkBuffExcept(t, val*-1))
 }
 
-func checkBuffExcept(buffId int) CheckFunc {
+func checkBuffExcept(t tenant.Model, buffId int) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return !character.HasBuff(l, span)(characterId, buffId)
+			return character.LacksBuff(l, span, t)(characterId, buffId)
 		}
 	}
 }
 
-func buffRequirement(sr xml.Noder) checkProducer {
+func buffRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromStringNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkBuff(val * -1))
+	return validRequirementProducer(checkBuff(t, val*-1))
 }
 
-func checkBuff(buffId int) CheckFunc {
+func checkBuff(t tenant.Model, buffId int) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.HasBuff(l, span)(characterId, buffId)
+			return character.HasBuff(l, span, t)(characterId, buffId)
 		}
 	}
 }
 
-func petRequirement(r xml.Noder) checkProducer {
+func petRequirement(t tenant.Model, r xml.Noder) checkProducer {
 	petIds := make([]uint32, 0)
 	prs, ok := r.(xml.Parent)
 	if !ok {
@@ -422,14 +606,13 @@ func petRequirement(r xml.Noder) checkProducer {
 		}
 		petIds = append(petIds, uint32(id))
 	}
-	return validRequirementProducer(checkPets(petIds))
+	return validRequirementProducer(checkPets(t, petIds))
 }
 
-func checkPets(ids []uint32) CheckFunc {
-	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32) bool {
-		return func(characterId uint32, npcId uint32) bool {
-			//TODO
-			return false
+func checkPets(t tenant.Model, ids []uint32) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return character.HasPet(l, span, t)(characterId, ids)
 		}
 	}
 }
@@ -459,9 +642,14 @@ func monsterBookRequirement(sr xml.Noder) checkProducer {
 }
 
 func checkMonsterBookCount(count uint32) CheckFunc {
-	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+	return func(l logrus.FieldLogger, _ opentracing.Span, db *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.MonsterBookCount(l, span)(characterId) >= count
+			c, err := monsterbook.CountCards(l, db)(characterId)
+			if err != nil {
+				l.WithError(err).Errorf("Unable to retrieve monster book for character %d. Assuming check fails.", characterId)
+				return false
+			}
+			return c >= count
 		}
 	}
 }
@@ -500,72 +688,71 @@ func checkMonster(questId uint16, monsters map[uint32]uint32) CheckFunc {
 	}
 }
 
-func petTamenessMinimumRequirement(sr xml.Noder) checkProducer {
+func petTamenessMinimumRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkMinTameness(val))
+	return validRequirementProducer(checkMinTameness(t, val))
 }
 
-func checkMinTameness(tameness int) CheckFunc {
-	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32) bool {
-		return func(characterId uint32, npcId uint32) bool {
-			//TODO
-			return false
+func checkMinTameness(t tenant.Model, tameness int) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return character.HasPetCloseness(l, span, t)(characterId, uint16(tameness))
 		}
 	}
 }
 
-func minLevelRequirement(sr xml.Noder) checkProducer {
+func minLevelRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkMinLevel(byte(val)))
+	return validRequirementProducer(checkMinLevel(t, byte(val)))
 }
 
-func checkMinLevel(level byte) CheckFunc {
+func checkMinLevel(t tenant.Model, level byte) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.IsMinimalLevel(l, span)(characterId, level)
+			return character.IsMinimalLevel(l, span, t)(characterId, level)
 		}
 	}
 }
 
-func mesoRequirement(sr xml.Noder) checkProducer {
+func mesoRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkMinMeso(uint32(val)))
+	return validRequirementProducer(checkMinMeso(t, uint32(val)))
 }
 
-func checkMinMeso(meso uint32) CheckFunc {
+func checkMinMeso(t tenant.Model, meso uint32) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.HasMinimalMeso(l, span)(characterId, meso)
+			return character.HasMinimalMeso(l, span, t)(characterId, meso)
 		}
 	}
 }
 
-func maxLevelRequirement(sr xml.Noder) checkProducer {
+func maxLevelRequirement(t tenant.Model, sr xml.Noder) checkProducer {
 	val, err := xml.IntFromIntegerNode(sr)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	return validRequirementProducer(checkMaxLevel(byte(val)))
+	return validRequirementProducer(checkMaxLevel(t, byte(val)))
 }
 
-func checkMaxLevel(level byte) CheckFunc {
+func checkMaxLevel(t tenant.Model, level byte) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.IsMaximalLevel(l, span)(characterId, level)
+			return character.IsMaximalLevel(l, span, t)(characterId, level)
 		}
 	}
 }
 
-func itemRequirement(r xml.Noder) checkProducer {
+func itemRequirement(t tenant.Model, r xml.Noder) checkProducer {
 	items := make(map[uint32]uint32)
 	irs, ok := r.(xml.Parent)
 	if !ok {
@@ -587,13 +774,13 @@ func itemRequirement(r xml.Noder) checkProducer {
 		}
 		items[uint32(id)] = uint32(count)
 	}
-	return validRequirementProducer(checkItems(items))
+	return validRequirementProducer(checkItems(t, items))
 }
 
-func checkItems(items map[uint32]uint32) CheckFunc {
+func checkItems(t tenant.Model, items map[uint32]uint32) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.HasItems(l, span)(characterId, items)
+			return character.HasItems(l, span, t)(characterId, items)
 		}
 	}
 }
@@ -657,7 +844,7 @@ func infoNumberRequirement(_ xml.Noder) checkProducer {
 	return validRequirementProducer(validCheck)
 }
 
-func fieldEnterRequirement(r xml.Noder) checkProducer {
+func fieldEnterRequirement(t tenant.Model, r xml.Noder) checkProducer {
 	mapId := uint32(0)
 	fr, ok := r.(xml.Parent)
 	if !ok {
@@ -668,13 +855,13 @@ func fieldEnterRequirement(r xml.Noder) checkProducer {
 	if err == nil {
 		mapId = uint32(zf)
 	}
-	return validRequirementProducer(checkMap(mapId))
+	return validRequirementProducer(checkMap(t, mapId))
 }
 
-func checkMap(mapId uint32) CheckFunc {
+func checkMap(t tenant.Model, mapId uint32) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.InMap(l, span)(characterId, mapId)
+			return character.InMap(l, span, t)(characterId, mapId)
 		}
 	}
 }
@@ -749,7 +936,56 @@ func getStatusById(id uint32) string {
 	}
 }
 
-func jobRequirement(r xml.Noder) checkProducer {
+func getEquipmentIds(r xml.Noder) ([]uint32, error) {
+	var ids []uint32
+	ers, ok := r.(xml.Parent)
+	if !ok {
+		return nil, errors.New("invalid xml structure")
+	}
+
+	for _, er := range ers.Children() {
+		id, err := xml.IntFromIntegerNode(er)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint32(id))
+	}
+	return ids, nil
+}
+
+func equipAllNeedRequirement(t tenant.Model, r xml.Noder) checkProducer {
+	ids, err := getEquipmentIds(r)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkEquipAll(t, ids))
+}
+
+func checkEquipAll(t tenant.Model, ids []uint32) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return inventory.IsWearingAll(l, span, t)(characterId, ids)
+		}
+	}
+}
+
+func equipSelectNeedRequirement(t tenant.Model, r xml.Noder) checkProducer {
+	ids, err := getEquipmentIds(r)
+	if err != nil {
+		return errorCheckProducer(err)
+	}
+	return validRequirementProducer(checkEquipSelect(t, ids))
+}
+
+func checkEquipSelect(t tenant.Model, ids []uint32) CheckFunc {
+	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
+		return func(characterId uint32, _ uint32) bool {
+			return inventory.IsWearingAny(l, span, t)(characterId, ids)
+		}
+	}
+}
+
+func jobRequirement(t tenant.Model, r xml.Noder) checkProducer {
 	var ids []uint16
 	jrs, ok := r.(xml.Parent)
 	if !ok {
@@ -767,13 +1003,13 @@ func jobRequirement(r xml.Noder) checkProducer {
 		}
 		ids = append(ids, uint16(id))
 	}
-	return validRequirementProducer(checkJobs(ids))
+	return validRequirementProducer(checkJobs(t, ids))
 }
 
-func checkJobs(ids []uint16) CheckFunc {
+func checkJobs(t tenant.Model, ids []uint16) CheckFunc {
 	return func(l logrus.FieldLogger, span opentracing.Span, _ *gorm.DB) func(characterId uint32, _ uint32) bool {
 		return func(characterId uint32, _ uint32) bool {
-			return character.IsJob(l, span)(characterId, ids)
+			return character.IsJob(l, span, t)(characterId, ids)
 		}
 	}
 }
@@ -783,29 +1019,19 @@ func endDateRequirement(r xml.Noder) checkProducer {
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-
-	year, err := strconv.Atoi(val[0:4])
+	endDate, err := event.ParseDate(val)
 	if err != nil {
 		return errorCheckProducer(err)
 	}
-	month, err := strconv.Atoi(val[4:6])
-	if err != nil {
-		return errorCheckProducer(err)
-	}
-	day, err := strconv.Atoi(val[6:8])
-	if err != nil {
-		return errorCheckProducer(err)
-	}
-	hod, err := strconv.Atoi(val[8:10])
-	if err != nil {
-		return errorCheckProducer(err)
-	}
-	endDate := time.Date(year, time.Month(month), day, hod, 0, 0, 0, time.Now().Location())
-	return validRequirementProducer(func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32) bool {
-		return func(characterId uint32, npcId uint32) bool {
-			return endDate.After(time.Now())
+	return validRequirementProducer(checkNotEnded(endDate))
+}
+
+func checkNotEnded(endDate time.Time) CheckFunc {
+	return func(_ logrus.FieldLogger, _ opentracing.Span, _ *gorm.DB) func(_ uint32, _ uint32) bool {
+		return func(_ uint32, _ uint32) bool {
+			return time.Now().Before(endDate)
 		}
-	})
+	}
 }
 
 func getByWZName(name string) (Type, error) {
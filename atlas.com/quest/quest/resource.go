@@ -1,30 +1,81 @@
 package quest
 
 import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/json"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/event"
 	"atlas-quest/rest"
+	"atlas-quest/rest/resource"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
-	getQuest = "get_quest"
+	getQuests           = "get_quests"
+	getQuest            = "get_quest"
+	getQuestDiagnostics = "get_quest_diagnostics"
+	getQuestScripts     = "get_quest_scripts"
+	clearCache          = "clear_cache"
+	getConversation     = "get_quest_conversation"
+	submitConversation  = "submit_quest_conversation"
+	getEventWindows     = "get_quest_event_windows"
+	overrideEventWindow = "override_quest_event_window"
+	clearEventWindow    = "clear_quest_event_window"
+	getQuestItem        = "get_quest_item"
+	getItemQuests       = "get_item_quests"
+	getSkillBooks       = "get_quest_skill_books"
+	getDropEligibility  = "get_quest_drop_eligibility"
+	getMobProgress      = "get_quest_mob_progress"
+	recordKill          = "record_quest_kill"
+	forfeitQuest        = "forfeit_quest"
+	getEligibility      = "get_quest_eligibility"
+	startQuest          = "start_quest"
+	completeQuest       = "complete_quest"
 )
 
-func InitResource(router *mux.Router, l logrus.FieldLogger, _ *gorm.DB) {
+func InitResource(router *mux.Router, l logrus.FieldLogger, db *gorm.DB) {
 	r := router.PathPrefix("/quests").Subrouter()
-	//r.HandleFunc("/", registerClearCache(l)).Methods(http.MethodDelete)
+	r.HandleFunc("/", registerGetQuests(l)).Methods(http.MethodGet)
+	r.HandleFunc("/", rest.RequireAdmin(l, registerClearCache(l))).Methods(http.MethodDelete)
 	//r.HandleFunc("/", registerGetQuestByInfoNumber(l)).Methods(http.MethodGet).Queries("infoNumber", "{infoNumber}", "filter[search]", "{filter}")
 	//r.HandleFunc("/{id}", registerGetQuestCheckEnd(l)).Methods(http.MethodGet).Queries("checkEnd", "{checkEnd}")
+	r.HandleFunc("/diagnostics", registerGetQuestDiagnostics(l)).Methods(http.MethodGet)
+	r.HandleFunc("/scripts", registerGetQuestScripts(l)).Methods(http.MethodGet)
+	r.HandleFunc("/events", registerGetEventWindows(l)).Methods(http.MethodGet)
+	r.HandleFunc("/items/skillBooks", registerGetSkillBooks(l)).Methods(http.MethodGet)
+	r.HandleFunc("/items/{itemId}", registerGetItemQuests(l)).Methods(http.MethodGet)
 	r.HandleFunc("/{id}", registerGetQuest(l)).Methods(http.MethodGet)
+	r.HandleFunc("/{id}/conversations/{phase}", registerGetQuestConversation(l)).Methods(http.MethodGet)
+	r.HandleFunc("/{id}/event-window", rest.RequireAdmin(l, registerOverrideEventWindow(l, db))).Methods(http.MethodPut)
+	r.HandleFunc("/{id}/event-window", rest.RequireAdmin(l, registerClearEventWindow(l, db))).Methods(http.MethodDelete)
 	//r.HandleFunc("/{id}/infoNumber", registerGetQuestInfoNumber(l)).Methods(http.MethodGet).Queries("status", "{status}")
 	//r.HandleFunc("/{id}/infoEx", registerGetQuestInfoNumberEx(l)).Methods(http.MethodGet).Queries("status", "{status}", "index", "{index}")
-	//r.HandleFunc("/{id}/items/{itemId}", registerGetQuestItemInformation(l)).Methods(http.MethodGet)
+	r.HandleFunc("/{id}/items/{itemId}", registerGetQuestItem(l)).Methods(http.MethodGet)
 	//r.HandleFunc("/{id}", registerClearQuestCache(l)).Methods(http.MethodDelete)
-	//r.HandleFunc("/items/skillBooks", registerGetSkillBooksFromQuests(l)).Methods(http.MethodGet)
+
+	cr := router.PathPrefix("/characters/{characterId}/quests").Subrouter()
+	cr.HandleFunc("/progress", registerGetMobProgress(l, db)).Methods(http.MethodGet)
+	cr.HandleFunc("/kills", registerRecordKill(l, db)).Methods(http.MethodPost)
+	cr.HandleFunc("/{id}/conversations/{phase}", registerSubmitQuestConversation(l, db)).Methods(http.MethodPost)
+	cr.HandleFunc("/{id}/forfeitures", registerForfeitQuest(l, db)).Methods(http.MethodPost)
+	cr.HandleFunc("/{id}/eligibility", registerGetEligibility(l, db)).Methods(http.MethodGet)
+	cr.HandleFunc("/{id}/starts", registerStartQuest(l, db)).Methods(http.MethodPost)
+	cr.HandleFunc("/{id}/completions", registerCompleteQuest(l, db)).Methods(http.MethodPost)
+
+	dr := router.PathPrefix("/characters/{characterId}/quest-drops").Subrouter()
+	dr.HandleFunc("/eligibility", registerGetDropEligibility(l, db)).Methods(http.MethodPost)
 }
 
 type IdHandler func(questId uint32) http.HandlerFunc
@@ -41,25 +92,920 @@ func ParseId(l logrus.FieldLogger, next IdHandler) http.HandlerFunc {
 	}
 }
 
+type CharacterIdHandler func(characterId uint32) http.HandlerFunc
+
+func ParseCharacterId(l logrus.FieldLogger, next CharacterIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		characterId, err := strconv.Atoi(mux.Vars(r)["characterId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse characterId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(characterId))(w, r)
+	}
+}
+
+type ItemIdHandler func(itemId uint32) http.HandlerFunc
+
+func ParseItemId(l logrus.FieldLogger, next ItemIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		itemId, err := strconv.Atoi(mux.Vars(r)["itemId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse itemId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(itemId))(w, r)
+	}
+}
+
+type PhaseHandler func(phase string) http.HandlerFunc
+
+func ParsePhase(l logrus.FieldLogger, next PhaseHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		phase := mux.Vars(r)["phase"]
+		if phase != conversation.PhaseStart && phase != conversation.PhaseComplete {
+			l.Errorf("Unable to properly parse phase from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(phase)(w, r)
+	}
+}
+
+func registerGetQuests(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getQuests, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return handleGetQuests(l, t)(span)
+		})
+	})
+}
+
+func handleGetQuests(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) http.HandlerFunc {
+	return func(span opentracing.Span) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			filters, err := parseCriteria(query)
+			if err != nil {
+				writeBadRequest(l, w, err)
+				return
+			}
+			number, size, err := parsePage(query)
+			if err != nil {
+				writeBadRequest(l, w, err)
+				return
+			}
+			fields := parseFields(query, "quests")
+
+			qs, total := Search(l, t)((number-1)*size, size, filters...)
+			results := make([]sparseDataBody, 0, len(qs))
+			for _, q := range qs {
+				b, err := makeSparseQuestBody(q, fields)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to make response body for quest %d.", q.Id())
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				results = append(results, b)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			err = json.ToJSON(listDataContainer{
+				Data:  results,
+				Meta:  listMeta{Total: total, Number: number, Size: size},
+				Links: makePageLinks(*r.URL, number, size, total),
+			}, w)
+			if err != nil {
+				l.WithError(err).Errorf("Writing response for quests.")
+			}
+		}
+	}
+}
+
 func registerGetQuest(l logrus.FieldLogger) http.HandlerFunc {
 	return rest.RetrieveSpan(getQuest, func(span opentracing.Span) http.HandlerFunc {
-		return ParseId(l, func(questId uint32) http.HandlerFunc {
-			return handleGetQuest(l)(span)(questId)
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseId(l, func(questId uint32) http.HandlerFunc {
+				return handleGetQuest(l, t)(span)(questId)
+			})
+		})
+	})
+}
+
+func handleGetQuest(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
+		return func(questId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				q, err := GetById(l, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(dataContainer{Data: makeQuestBody(q)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for quest %d.", questId)
+				}
+			}
+		}
+	}
+}
+
+func registerGetQuestDiagnostics(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getQuestDiagnostics, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return handleGetQuestDiagnostics(l, t)(span)
+		})
+	})
+}
+
+func handleGetQuestDiagnostics(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) http.HandlerFunc {
+	return func(span opentracing.Span) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			ds := GetDiagnostics(l, t)
+
+			w.WriteHeader(http.StatusOK)
+			err := json.ToJSON(diagnosticListDataContainer{Data: makeDiagnosticBodies(ds)}, w)
+			if err != nil {
+				l.WithError(err).Errorf("Writing response for quest diagnostics.")
+			}
+		}
+	}
+}
+
+func registerGetQuestScripts(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getQuestScripts, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return handleGetQuestScripts(l, t)(span)
+		})
+	})
+}
+
+func handleGetQuestScripts(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) http.HandlerFunc {
+	return func(span opentracing.Span) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			rs := GetScripts(l, t)
+
+			w.WriteHeader(http.StatusOK)
+			err := json.ToJSON(scriptListDataContainer{Data: makeScriptBodies(rs)}, w)
+			if err != nil {
+				l.WithError(err).Errorf("Writing response for quest scripts.")
+			}
+		}
+	}
+}
+
+func registerClearCache(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(clearCache, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return handleClearCache(l, t)(span)
+		})
+	})
+}
+
+func handleClearCache(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) http.HandlerFunc {
+	return func(span opentracing.Span) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+			err := Reload(l, t, force)
+			if errors.As(err, &RegressionError{}) {
+				w.WriteHeader(http.StatusConflict)
+				err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing error response.")
+				}
+				return
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing error response.")
+				}
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+func registerGetQuestConversation(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getConversation, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseId(l, func(questId uint32) http.HandlerFunc {
+				return ParsePhase(l, func(phase string) http.HandlerFunc {
+					return handleGetQuestConversation(l, t)(span)(questId, phase)
+				})
+			})
+		})
+	})
+}
+
+func handleGetQuestConversation(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) func(questId uint32, phase string) http.HandlerFunc {
+	return func(span opentracing.Span) func(questId uint32, phase string) http.HandlerFunc {
+		return func(questId uint32, phase string) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				c, err := GetConversation(l, t)(questId, phase)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(conversationDataContainer{Data: makeConversationBody(uint16(questId), phase, c)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for quest %d %s conversation.", questId, phase)
+				}
+			}
+		}
+	}
+}
+
+func registerSubmitQuestConversation(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(submitConversation, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint32) http.HandlerFunc {
+					return ParsePhase(l, func(phase string) http.HandlerFunc {
+						return handleSubmitQuestConversation(l, t.Database(db), t)(span)(characterId, questId, phase)
+					})
+				})
+			})
+		})
+	})
+}
+
+func handleSubmitQuestConversation(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(characterId uint32, questId uint32, phase string) http.HandlerFunc {
+	return func(span opentracing.Span) func(characterId uint32, questId uint32, phase string) http.HandlerFunc {
+		return func(characterId uint32, questId uint32, phase string) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				input := &answerInputDataContainer{}
+				err := json.FromJSON(input, r.Body)
+				if err != nil {
+					l.WithError(err).Errorf("Deserializing input.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				_, err = GetById(l, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				attr := input.Data.Attributes
+				res, err := SubmitConversation(l, db, t)(characterId, questId, phase, attr.Selections, attr.Accepted)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(resultDataContainer{Data: makeResultBody(characterId, uint16(questId), phase, res)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for character %d quest %d %s conversation.", characterId, questId, phase)
+				}
+			}
+		}
+	}
+}
+
+func registerGetEventWindows(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getEventWindows, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return handleGetEventWindows(l, t)(span)
+		})
+	})
+}
+
+func handleGetEventWindows(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) http.HandlerFunc {
+	return func(span opentracing.Span) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			ws := GetEventWindows(l, t)(now)
+
+			if status := r.URL.Query().Get("status"); status != "" {
+				filtered := make([]event.Window, 0)
+				for _, ew := range ws {
+					if strings.EqualFold(ew.Status(now), status) {
+						filtered = append(filtered, ew)
+					}
+				}
+				ws = filtered
+			}
+
+			w.WriteHeader(http.StatusOK)
+			err := json.ToJSON(eventWindowListDataContainer{Data: makeEventWindowBodies(ws, now)}, w)
+			if err != nil {
+				l.WithError(err).Errorf("Writing response for quest event windows.")
+			}
+		}
+	}
+}
+
+func registerOverrideEventWindow(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(overrideEventWindow, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseId(l, func(questId uint32) http.HandlerFunc {
+				return handleOverrideEventWindow(l, t.Database(db), t)(span)(questId)
+			})
 		})
 	})
 }
 
-func handleGetQuest(l logrus.FieldLogger) func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
+func handleOverrideEventWindow(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
 	return func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
 		return func(questId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				input := &eventWindowInputDataContainer{}
+				err := json.FromJSON(input, r.Body)
+				if err != nil {
+					l.WithError(err).Errorf("Deserializing input.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				start, err := parseOptionalDate(input.Data.Attributes.Start)
+				if err != nil {
+					writeBadRequest(l, w, err)
+					return
+				}
+				end, err := parseOptionalDate(input.Data.Attributes.End)
+				if err != nil {
+					writeBadRequest(l, w, err)
+					return
+				}
+
+				_, err = GetById(l, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				ew, err := OverrideEventWindow(l, db, t)(questId, start, end)
+				if err != nil {
+					writeBadRequest(l, w, err)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(eventWindowDataContainer{Data: makeEventWindowBody(ew, time.Now())}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for quest %d event window.", questId)
+				}
+			}
+		}
+	}
+}
+
+func registerClearEventWindow(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(clearEventWindow, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseId(l, func(questId uint32) http.HandlerFunc {
+				return handleClearEventWindow(l, t.Database(db), t)(span)(questId)
+			})
+		})
+	})
+}
+
+func handleClearEventWindow(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(questId uint32) http.HandlerFunc {
+		return func(questId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				_, err := GetById(l, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				err = ClearEventWindow(l, db, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}
+	}
+}
+
+func registerGetQuestItem(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getQuestItem, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseId(l, func(questId uint32) http.HandlerFunc {
+				return ParseItemId(l, func(itemId uint32) http.HandlerFunc {
+					return handleGetQuestItem(l, t)(span)(questId, itemId)
+				})
+			})
+		})
+	})
+}
+
+func handleGetQuestItem(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) func(questId uint32, itemId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(questId uint32, itemId uint32) http.HandlerFunc {
+		return func(questId uint32, itemId uint32) http.HandlerFunc {
 			return func(w http.ResponseWriter, _ *http.Request) {
-				_, err := GetById(l)(questId)
+				u, err := GetItemUsage(l, t)(questId, itemId)
 				if err != nil {
 					w.WriteHeader(http.StatusNotFound)
 					return
 				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(itemUsageDataContainer{Data: makeItemUsageBody(u)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for quest %d item %d.", questId, itemId)
+				}
+			}
+		}
+	}
+}
+
+func registerGetItemQuests(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getItemQuests, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseItemId(l, func(itemId uint32) http.HandlerFunc {
+				return handleGetItemQuests(l, t)(span)(itemId)
+			})
+		})
+	})
+}
+
+func handleGetItemQuests(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) func(itemId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(itemId uint32) http.HandlerFunc {
+		return func(itemId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				err := json.ToJSON(itemQuestsDataContainer{Data: makeItemQuestsBody(GetItemQuests(l, t)(itemId))}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for item %d quests.", itemId)
+				}
+			}
+		}
+	}
+}
+
+func registerGetSkillBooks(l logrus.FieldLogger) http.HandlerFunc {
+	return rest.RetrieveSpan(getSkillBooks, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return handleGetSkillBooks(l, t)(span)
+		})
+	})
+}
+
+func handleGetSkillBooks(l logrus.FieldLogger, t tenant.Model) func(span opentracing.Span) http.HandlerFunc {
+	return func(span opentracing.Span) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			err := json.ToJSON(itemQuestsListDataContainer{Data: makeItemQuestsBodies(GetSkillBooks(l, t))}, w)
+			if err != nil {
+				l.WithError(err).Errorf("Writing response for quest skill books.")
+			}
+		}
+	}
+}
+
+func registerGetDropEligibility(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getDropEligibility, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return handleGetDropEligibility(l, t.Database(db), t)(span)(characterId)
+			})
+		})
+	})
+}
+
+func handleGetDropEligibility(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(characterId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(characterId uint32) http.HandlerFunc {
+		return func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				input := &dropEligibilityInputDataContainer{}
+				err := json.FromJSON(input, r.Body)
+				if err != nil {
+					l.WithError(err).Errorf("Deserializing input.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				ids, err := GetDropEligibility(l, span, db, t)(characterId, input.Data.Attributes.ItemIds)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to determine quest drop eligibility for character %d.", characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(dropEligibilityDataContainer{Data: makeDropEligibilityBody(characterId, ids)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for character %d quest drop eligibility.", characterId)
+				}
+			}
+		}
+	}
+}
+
+func registerGetMobProgress(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getMobProgress, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return handleGetMobProgress(l, t.Database(db), t)(span)(characterId)
+			})
+		})
+	})
+}
+
+func handleGetMobProgress(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(characterId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(characterId uint32) http.HandlerFunc {
+		return func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				mobId, err := strconv.ParseUint(r.URL.Query().Get("mobId"), 10, 32)
+				if err != nil {
+					writeBadRequest(l, w, errors.New("mobId must be provided"))
+					return
+				}
+
+				ps, err := GetMobProgress(l, span, db, t)(characterId, uint32(mobId))
+				if err != nil {
+					l.WithError(err).Errorf("Unable to retrieve monster %d quest progress for character %d.", mobId, characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(progressListDataContainer{Data: makeProgressBodies(ps)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for character %d quest progress.", characterId)
+				}
+			}
+		}
+	}
+}
+
+func registerRecordKill(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(recordKill, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return handleRecordKill(l, t.Database(db), t)(span)(characterId)
+			})
+		})
+	})
+}
+
+func handleRecordKill(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(characterId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(characterId uint32) http.HandlerFunc {
+		return func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				input := &killInputDataContainer{}
+				err := json.FromJSON(input, r.Body)
+				if err != nil {
+					l.WithError(err).Errorf("Deserializing input.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				attr := input.Data.Attributes
+				party := make([]PartyMember, 0, len(attr.Party))
+				for _, m := range attr.Party {
+					party = append(party, NewPartyMember(m.CharacterId, m.MapId))
+				}
+				ps, err := RecordKill(l, span, db, t)(characterId, attr.MobId, attr.MapId, party)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to record kill of monster %d by character %d.", attr.MobId, characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(progressListDataContainer{Data: makeProgressBodies(ps)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for character %d kill.", characterId)
+				}
+			}
+		}
+	}
+}
+
+func registerForfeitQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(forfeitQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint32) http.HandlerFunc {
+					return handleForfeitQuest(l, t.Database(db), t)(span)(characterId, questId)
+				})
+			})
+		})
+	})
+}
+
+func handleForfeitQuest(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(characterId uint32, questId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(characterId uint32, questId uint32) http.HandlerFunc {
+		return func(characterId uint32, questId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				_, err := GetById(l, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				f, err := Forfeit(l, span, db, t)(characterId, questId)
+				if errors.Is(err, ErrNotForfeitable) || errors.Is(err, ErrNotStarted) {
+					w.WriteHeader(http.StatusConflict)
+					err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+					if err != nil {
+						l.WithError(err).Errorf("Writing error response.")
+					}
+					return
+				}
+				if err != nil {
+					l.WithError(err).Errorf("Unable to forfeit quest %d for character %d.", questId, characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.WriteHeader(http.StatusCreated)
+				err = json.ToJSON(forfeitureDataContainer{Data: makeForfeitureBody(f)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for character %d quest %d forfeiture.", characterId, questId)
+				}
+			}
+		}
+	}
+}
+
+func registerGetEligibility(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(getEligibility, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint32) http.HandlerFunc {
+					return handleGetEligibility(l, t.Database(db), t)(span)(characterId, questId)
+				})
+			})
+		})
+	})
+}
+
+func handleGetEligibility(l logrus.FieldLogger, db *gorm.DB, t tenant.Model) func(span opentracing.Span) func(characterId uint32, questId uint32) http.HandlerFunc {
+	return func(span opentracing.Span) func(characterId uint32, questId uint32) http.HandlerFunc {
+		return func(characterId uint32, questId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				var npcId uint64
+				if val := r.URL.Query().Get("npcId"); val != "" {
+					var err error
+					npcId, err = strconv.ParseUint(val, 10, 32)
+					if err != nil {
+						writeBadRequest(l, w, errors.New("npcId must be a number"))
+						return
+					}
+				}
+
+				_, err := GetById(l, t)(questId)
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				e, err := GetEligibility(l, span, db, t)(characterId, questId, uint32(npcId))
+				if err != nil {
+					l.WithError(err).Errorf("Unable to evaluate quest %d eligibility for character %d.", questId, characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
 				w.WriteHeader(http.StatusOK)
+				err = json.ToJSON(eligibilityDataContainer{Data: makeEligibilityBody(e)}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing response for character %d quest %d eligibility.", characterId, questId)
+				}
+			}
+		}
+	}
+}
+
+func registerStartQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(startQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint32) http.HandlerFunc {
+					return handleTransition(l, t, Start(l, span, t.Database(db), t))(characterId, questId)
+				})
+			})
+		})
+	})
+}
+
+func registerCompleteQuest(l logrus.FieldLogger, db *gorm.DB) http.HandlerFunc {
+	return rest.RetrieveSpan(completeQuest, func(span opentracing.Span) http.HandlerFunc {
+		return rest.ParseTenant(l, func(t tenant.Model) http.HandlerFunc {
+			return ParseCharacterId(l, func(characterId uint32) http.HandlerFunc {
+				return ParseId(l, func(questId uint32) http.HandlerFunc {
+					return handleTransition(l, t, Complete(l, span, t.Database(db), t))(characterId, questId)
+				})
+			})
+		})
+	})
+}
+
+// TransitionFunc moves the character's standing in the quest, speaking with the npc.
+type TransitionFunc func(characterId uint32, questId uint32, npcId uint32, selection int) (characterquest.Model, error)
+
+// handleTransition starts or completes the quest for the character. Unmet requirements and a standing the quest cannot
+// move from are reported as conflicts.
+func handleTransition(l logrus.FieldLogger, t tenant.Model, f TransitionFunc) func(characterId uint32, questId uint32) http.HandlerFunc {
+	return func(characterId uint32, questId uint32) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			input := &transitionInputDataContainer{}
+			err := json.FromJSON(input, r.Body)
+			if err != nil {
+				l.WithError(err).Errorf("Deserializing input.")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			_, err = GetById(l, t)(questId)
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			attr := input.Data.Attributes
+			m, err := f(characterId, questId, attr.NpcId, attr.Selection)
+			var re RequirementError
+			if errors.As(err, &re) || errors.Is(err, ErrAlreadyStarted) || errors.Is(err, ErrAlreadyCompleted) || errors.Is(err, ErrNotStarted) {
+				w.WriteHeader(http.StatusConflict)
+				err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+				if err != nil {
+					l.WithError(err).Errorf("Writing error response.")
+				}
+				return
+			}
+			if err != nil {
+				l.WithError(err).Errorf("Unable to update quest %d for character %d.", questId, characterId)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
 			}
+
+			w.WriteHeader(http.StatusCreated)
+			err = json.ToJSON(statusDataContainer{Data: makeStatusBody(characterId, m)}, w)
+			if err != nil {
+				l.WithError(err).Errorf("Writing response for character %d quest %d.", characterId, questId)
+			}
+		}
+	}
+}
+
+// parseOptionalDate reads a YYYYMMDDHH date in the server timezone. An empty value yields the zero time.
+func parseOptionalDate(val string) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+	return event.ParseDate(val)
+}
+
+func writeBadRequest(l logrus.FieldLogger, w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	err = json.ToJSON(&resource.GenericError{Message: err.Error()}, w)
+	if err != nil {
+		l.WithError(err).Errorf("Writing error response.")
+	}
+}
+
+const (
+	defaultPageSize = 50
+	maximumPageSize = 500
+	// maximumPageNumber keeps the offset of the page within range of an int at any page size.
+	maximumPageNumber = math.MaxInt / maximumPageSize
+)
+
+// parseCriteria reads the filter[...] query parameters into search criteria.
+func parseCriteria(query url.Values) ([]Criteria, error) {
+	results := make([]Criteria, 0)
+	if v := query.Get("filter[name]"); v != "" {
+		results = append(results, NameContains(v))
+	}
+	if v := query.Get("filter[parent]"); v != "" {
+		results = append(results, HasParent(v))
+	}
+
+	uints := []struct {
+		key  string
+		bits int
+		f    func(uint64) Criteria
+	}{
+		{"filter[area]", 32, func(v uint64) Criteria { return InArea(uint32(v)) }},
+		{"filter[job]", 16, func(v uint64) Criteria { return ForJob(uint16(v)) }},
+		{"filter[mobId]", 32, func(v uint64) Criteria { return RelevantTo(uint32(v)) }},
+		{"filter[rewardItemId]", 32, func(v uint64) Criteria { return Rewards(uint32(v)) }},
+		{"filter[requiredItemId]", 32, func(v uint64) Criteria { return Requires(uint32(v)) }},
+		{"filter[consumedItemId]", 32, func(v uint64) Criteria { return Consumes(uint32(v)) }},
+	}
+	for _, u := range uints {
+		if v := query.Get(u.key); v != "" {
+			val, err := strconv.ParseUint(v, 10, u.bits)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid %s value [%s]", u.key, v))
+			}
+			results = append(results, u.f(val))
 		}
 	}
+
+	bools := []struct {
+		key string
+		f   func(bool) Criteria
+	}{
+		{"filter[autoStart]", IsAutoStart},
+		{"filter[repeatable]", IsRepeatable},
+		{"filter[medal]", AwardsMedal},
+	}
+	for _, b := range bools {
+		if v := query.Get(b.key); v != "" {
+			val, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid %s value [%s]", b.key, v))
+			}
+			results = append(results, b.f(val))
+		}
+	}
+
+	minimum, err := parseLevel(query, "filter[minLevel]")
+	if err != nil {
+		return nil, err
+	}
+	maximum, err := parseLevel(query, "filter[maxLevel]")
+	if err != nil {
+		return nil, err
+	}
+	if minimum > 0 && maximum > 0 && minimum > maximum {
+		return nil, errors.New("filter[minLevel] must not exceed filter[maxLevel]")
+	}
+	if minimum > 0 || maximum > 0 {
+		results = append(results, StartableWithin(minimum, maximum))
+	}
+	return results, nil
+}
+
+func parseLevel(query url.Values, key string) (byte, error) {
+	v := query.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	val, err := strconv.ParseUint(v, 10, 8)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("invalid %s value [%s]", key, v))
+	}
+	return byte(val), nil
+}
+
+// parsePage reads the 1-based page[number] and the page[size] query parameters.
+func parsePage(query url.Values) (int, int, error) {
+	number := 1
+	size := defaultPageSize
+	if v := query.Get("page[number]"); v != "" {
+		val, err := strconv.Atoi(v)
+		if err != nil || val < 1 || val > maximumPageNumber {
+			return 0, 0, errors.New(fmt.Sprintf("page[number] must be from 1 to %d", maximumPageNumber))
+		}
+		number = val
+	}
+	if v := query.Get("page[size]"); v != "" {
+		val, err := strconv.Atoi(v)
+		if err != nil || val < 1 || val > maximumPageSize {
+			return 0, 0, errors.New(fmt.Sprintf("page[size] must be from 1 to %d", maximumPageSize))
+		}
+		size = val
+	}
+	return number, size, nil
+}
+
+// parseFields reads the sparse fieldset requested for the resource type. An empty result requests all fields.
+func parseFields(query url.Values, resourceType string) []string {
+	results := make([]string, 0)
+	for _, f := range strings.Split(query.Get("fields["+resourceType+"]"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			results = append(results, f)
+		}
+	}
+	return results
+}
+
+// makePageLinks produces the pagination links for the request, retaining its other query parameters.
+func makePageLinks(u url.URL, number int, size int, total int) listLinks {
+	last := (total + size - 1) / size
+	if last < 1 {
+		last = 1
+	}
+	link := func(n int) string {
+		query := u.Query()
+		query.Set("page[number]", strconv.Itoa(n))
+		query.Set("page[size]", strconv.Itoa(size))
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	links := listLinks{Self: link(number), First: link(1), Last: link(last)}
+	if number > 1 {
+		links.Prev = link(number - 1)
+	}
+	if number < last {
+		links.Next = link(number + 1)
+	}
+	return links
 }
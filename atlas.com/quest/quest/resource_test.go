@@ -0,0 +1,76 @@
+package quest
+
+import (
+	"errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetQuestsPaging(t *testing.T) {
+	tm := loadQuests(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(getQuests)
+
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"page[number]=1&page[size]=10", http.StatusOK},
+		{"page[number]=100000&page[size]=500", http.StatusOK},
+		{"page[number]=18446744073709551&page[size]=500", http.StatusOK},
+		{"page[number]=18446744073709552&page[size]=500", http.StatusBadRequest},
+		{"page[number]=9223372036854775807", http.StatusBadRequest},
+		{"page[number]=9223372036854775807&page[size]=1", http.StatusBadRequest},
+		{"page[number]=0", http.StatusBadRequest},
+		{"page[size]=501", http.StatusBadRequest},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/ms/quest/quests?"+tc.query, nil)
+		w := httptest.NewRecorder()
+		handleGetQuests(l, tm)(span)(w, r)
+		if w.Code != tc.want {
+			t.Errorf("GET /quests?%s = %d, want %d", tc.query, w.Code, tc.want)
+		}
+	}
+}
+
+func TestSearchClampsOffset(t *testing.T) {
+	tm := loadQuests(t)
+	total := len(GetCache(tm).GetQuests())
+
+	for _, tc := range []struct {
+		offset int
+		limit  int
+		want   int
+	}{
+		{0, 10, 10},
+		{-5, 10, 10},
+		{total - 3, 10, 3},
+		{total, 10, 0},
+		{math.MaxInt, 10, 0},
+		{total - 3, math.MaxInt, 3},
+	} {
+		qs, got := Search(nil, tm)(tc.offset, tc.limit)
+		if got != total || len(qs) != tc.want {
+			t.Errorf("Search(%d, %d) = %d of %d, want %d of %d", tc.offset, tc.limit, len(qs), got, tc.want, total)
+		}
+	}
+}
+
+func TestGetByIdRejectsWideIds(t *testing.T) {
+	tm := loadQuests(t)
+
+	if _, err := GetById(nil, tm)(4513); err != nil {
+		t.Fatal(err)
+	}
+	_, err := GetById(nil, tm)(math.MaxUint16 + 1 + 4513)
+	var nfe NotFoundError
+	if !errors.As(err, &nfe) || nfe.Id != math.MaxUint16+1+4513 {
+		t.Fatalf("GetById(%d) = %v, want quest not found", math.MaxUint16+1+4513, err)
+	}
+}
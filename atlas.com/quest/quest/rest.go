@@ -0,0 +1,299 @@
+package quest
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/diagnostic"
+	"atlas-quest/quest/event"
+	"atlas-quest/quest/requirement"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func makeQuestBody(m Model) dataBody {
+	return dataBody{
+		Id:   strconv.Itoa(int(m.Id())),
+		Type: "quests",
+		Attributes: attributes{
+			Name:                m.Name(),
+			ParentName:          m.Parent(),
+			TimeLimit:           m.TimeLimit(),
+			TimeLimit2:          m.TimeLimit2(),
+			AutoStart:           m.AutoStart(),
+			AutoPreComplete:     m.AutoPreComplete(),
+			AutoComplete:        m.AutoComplete(),
+			AutoAccept:          m.AutoAccept(),
+			Repeatable:          m.Repeatable(),
+			MedalId:             m.MedalId(),
+			MedalCategory:       m.MedalCategory(),
+			Area:                m.Area(),
+			Order:               m.Order(),
+			SortKey:             m.SortKey(),
+			QuestType:           m.Type(),
+			Summary:             m.Summary(),
+			DemandSummary:       m.DemandSummary(),
+			RewardSummary:       m.RewardSummary(),
+			SelectedMob:         m.SelectedMob(),
+			SelectedSkillId:     m.SelectedSkillId(),
+			TimerUI:             m.TimerUI(),
+			ShowLayerTag:        m.ShowLayerTag(),
+			OneShot:             m.OneShot(),
+			DailyPlayTime:       m.DailyPlayTime(),
+			StartDescription:    m.StartDescription(),
+			ProgressDescription: m.ProgressDescription(),
+			CompleteDescription: m.CompleteDescription(),
+		},
+	}
+}
+
+// makeSparseQuestBody restricts the quest attributes to the named fields. When no fields are named, all are included.
+func makeSparseQuestBody(m Model, fields []string) (sparseDataBody, error) {
+	b := makeQuestBody(m)
+	raw, err := json.Marshal(b.Attributes)
+	if err != nil {
+		return sparseDataBody{}, err
+	}
+	all := make(map[string]interface{})
+	err = json.Unmarshal(raw, &all)
+	if err != nil {
+		return sparseDataBody{}, err
+	}
+
+	attr := all
+	if len(fields) > 0 {
+		attr = make(map[string]interface{})
+		for _, f := range fields {
+			if v, ok := all[f]; ok {
+				attr[f] = v
+			}
+		}
+	}
+	return sparseDataBody{Id: b.Id, Type: b.Type, Attributes: attr}, nil
+}
+
+func makeDiagnosticBodies(ds []diagnostic.Model) []diagnosticDataBody {
+	results := make([]diagnosticDataBody, 0)
+	for i, d := range ds {
+		results = append(results, diagnosticDataBody{
+			Id:   strconv.Itoa(i),
+			Type: "diagnostics",
+			Attributes: diagnosticAttributes{
+				QuestId: d.QuestId(),
+				Phase:   string(d.Phase()),
+				Path:    d.Path(),
+				Error:   d.Err().Error(),
+				Skipped: d.Skipped(),
+			},
+		})
+	}
+	return results
+}
+
+func makeConversationBody(questId uint16, phase string, m conversation.Model) conversationDataBody {
+	steps := make([]stepAttributes, 0)
+	for _, s := range m.Steps() {
+		steps = append(steps, stepAttributes{
+			Text:     s.Text(),
+			Question: s.Question(),
+			Answer:   s.Answer(),
+			Stop:     s.Stop(),
+		})
+	}
+	return conversationDataBody{
+		Id:   phase,
+		Type: "conversations",
+		Attributes: conversationAttributes{
+			QuestId: questId,
+			Phase:   phase,
+			Steps:   steps,
+			Ask:     m.Ask(),
+			Yes:     m.Yes(),
+			No:      m.No(),
+		},
+	}
+}
+
+func makeResultBody(characterId uint32, questId uint16, phase string, r conversation.Result) resultDataBody {
+	return resultDataBody{
+		Id:   phase,
+		Type: "conversation-results",
+		Attributes: resultAttributes{
+			CharacterId: characterId,
+			QuestId:     questId,
+			Phase:       phase,
+			Passed:      r.Passed(),
+			Step:        r.Step(),
+			Dialogue:    r.Dialogue(),
+		},
+	}
+}
+
+func makeScriptBodies(rs []ScriptReference) []scriptDataBody {
+	results := make([]scriptDataBody, 0)
+	for _, r := range rs {
+		results = append(results, scriptDataBody{
+			Id:   r.Name(),
+			Type: "scripts",
+			Attributes: scriptAttributes{
+				Registered: r.Registered(),
+				Quests:     r.Quests(),
+			},
+		})
+	}
+	return results
+}
+
+func makeEventWindowBody(w event.Window, t time.Time) eventWindowDataBody {
+	return eventWindowDataBody{
+		Id:   strconv.Itoa(int(w.QuestId())),
+		Type: "event-windows",
+		Attributes: eventWindowAttributes{
+			Start:      event.FormatDate(w.Start()),
+			End:        event.FormatDate(w.End()),
+			Status:     w.Status(t),
+			Overridden: w.Overridden(),
+		},
+	}
+}
+
+func makeEventWindowBodies(ws []event.Window, t time.Time) []eventWindowDataBody {
+	results := make([]eventWindowDataBody, 0)
+	for _, w := range ws {
+		results = append(results, makeEventWindowBody(w, t))
+	}
+	return results
+}
+
+func makeItemUsageBody(u ItemUsage) itemUsageDataBody {
+	return itemUsageDataBody{
+		Id:   strconv.Itoa(int(u.ItemId())),
+		Type: "quest-items",
+		Attributes: itemUsageAttributes{
+			QuestId:       u.QuestId(),
+			StartCount:    u.StartCount(),
+			CompleteCount: u.CompleteCount(),
+			Granted:       u.Granted(),
+			StartGranted:  u.StartGranted(),
+			Taken:         u.Taken(),
+		},
+	}
+}
+
+func makeItemQuestsBody(q ItemQuests) itemQuestsDataBody {
+	return itemQuestsDataBody{
+		Id:   strconv.Itoa(int(q.ItemId())),
+		Type: "item-quests",
+		Attributes: itemQuestsAttributes{
+			Required: q.Required(),
+			Rewarded: q.Rewarded(),
+			Consumed: q.Consumed(),
+		},
+	}
+}
+
+func makeItemQuestsBodies(qs []ItemQuests) []itemQuestsDataBody {
+	results := make([]itemQuestsDataBody, 0)
+	for _, q := range qs {
+		results = append(results, makeItemQuestsBody(q))
+	}
+	return results
+}
+
+func makeDropEligibilityBody(characterId uint32, itemIds []uint32) dropEligibilityDataBody {
+	return dropEligibilityDataBody{
+		Id:         strconv.Itoa(int(characterId)),
+		Type:       "quest-drop-eligibility",
+		Attributes: dropEligibilityAttributes{ItemIds: itemIds},
+	}
+}
+
+func makeProgressBodies(ps []Progress) []progressDataBody {
+	results := make([]progressDataBody, 0)
+	for _, p := range ps {
+		results = append(results, progressDataBody{
+			Id:   fmt.Sprintf("%d:%d", p.CharacterId(), p.QuestId()),
+			Type: "quest-progress",
+			Attributes: progressAttributes{
+				CharacterId: p.CharacterId(),
+				QuestId:     p.QuestId(),
+				MobId:       p.MobId(),
+				Count:       p.Count(),
+				Required:    p.Required(),
+				Progress:    p.Encoded(),
+			},
+		})
+	}
+	return results
+}
+
+func makeForfeitureBody(f Forfeiture) forfeitureDataBody {
+	removed := make([]removedItemAttributes, 0, len(f.Removed()))
+	for itemId, quantity := range f.Removed() {
+		removed = append(removed, removedItemAttributes{ItemId: itemId, Quantity: quantity})
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].ItemId < removed[j].ItemId
+	})
+
+	var availableAt *time.Time
+	if !f.AvailableAt().IsZero() {
+		at := f.AvailableAt()
+		availableAt = &at
+	}
+	return forfeitureDataBody{
+		Id:   strconv.Itoa(int(f.QuestId())),
+		Type: "quest-forfeitures",
+		Attributes: forfeitureAttributes{
+			Forfeits:    f.Forfeits(),
+			AvailableAt: availableAt,
+			Removed:     removed,
+		},
+	}
+}
+
+func makeStatusBody(characterId uint32, m characterquest.Model) statusDataBody {
+	return statusDataBody{
+		Id:   strconv.Itoa(int(m.Id())),
+		Type: "quest-statuses",
+		Attributes: statusAttributes{
+			CharacterId: characterId,
+			QuestId:     m.Id(),
+			Status:      m.Status(),
+			StartedAt:   optionalTime(m.Started()),
+			CompletedAt: optionalTime(m.Completion()),
+			Info:        m.Info(),
+		},
+	}
+}
+
+func makeEligibilityBody(e Eligibility) eligibilityDataBody {
+	return eligibilityDataBody{
+		Id:   strconv.Itoa(int(e.QuestId())),
+		Type: "quest-eligibility",
+		Attributes: eligibilityAttributes{
+			Status:        e.Status(),
+			CanStart:      e.CanStart(),
+			CanComplete:   e.CanComplete(),
+			UnmetStart:    requirementNames(e.UnmetStart()),
+			UnmetComplete: requirementNames(e.UnmetComplete()),
+		},
+	}
+}
+
+func requirementNames(rts []requirement.Type) []string {
+	results := make([]string, 0, len(rts))
+	for _, rt := range rts {
+		results = append(results, string(rt))
+	}
+	return results
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,58 @@
+package script
+
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Func is a Go implemented hook, invoked in place of a script named by the quest data. It reports whether the quest
+// may proceed.
+type Func func(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(c Context) bool
+
+// Context describes the evaluation a script was invoked for.
+type Context struct {
+	name        string
+	questId     uint16
+	characterId uint32
+	npcId       uint32
+	selection   int
+	action      bool
+}
+
+func NewContext(name string, questId uint16, characterId uint32, npcId uint32, selection int) Context {
+	return Context{name: name, questId: questId, characterId: characterId, npcId: npcId, selection: selection}
+}
+
+// NewActionContext describes the script being invoked to apply the actions of the quest, once the quest has been
+// started or completed.
+func NewActionContext(name string, questId uint16, characterId uint32, npcId uint32, selection int) Context {
+	return Context{name: name, questId: questId, characterId: characterId, npcId: npcId, selection: selection, action: true}
+}
+
+func (c Context) Name() string {
+	return c.name
+}
+
+func (c Context) QuestId() uint16 {
+	return c.questId
+}
+
+func (c Context) CharacterId() uint32 {
+	return c.characterId
+}
+
+func (c Context) NpcId() uint32 {
+	return c.npcId
+}
+
+// Selection is the menu option chosen in the NPC conversation, when invoked for an action.
+func (c Context) Selection() int {
+	return c.selection
+}
+
+// Action reports whether the script was invoked to apply the actions of the quest, rather than to check its
+// requirements.
+func (c Context) Action() bool {
+	return c.action
+}
@@ -0,0 +1,78 @@
+package script
+
+import (
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+	"sync"
+)
+
+type registry struct {
+	scripts map[string]map[string]Func
+	lock    sync.RWMutex
+}
+
+var once sync.Once
+var r *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		r = &registry{
+			scripts: make(map[string]map[string]Func),
+			lock:    sync.RWMutex{},
+		}
+	})
+	return r
+}
+
+// Register makes f available to the tenant under name, replacing any hook previously registered with it.
+func (r *registry) Register(tenantId string, name string, f Func) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.scripts[tenantId]; !ok {
+		r.scripts[tenantId] = make(map[string]Func)
+	}
+	r.scripts[tenantId][name] = f
+}
+
+func (r *registry) Get(tenantId string, name string) (Func, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	f, ok := r.scripts[tenantId][name]
+	return f, ok
+}
+
+func (r *registry) IsRegistered(tenantId string, name string) bool {
+	_, ok := r.Get(tenantId, name)
+	return ok
+}
+
+func (r *registry) Names(tenantId string) []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	results := make([]string, 0, len(r.scripts[tenantId]))
+	for name := range r.scripts[tenantId] {
+		results = append(results, name)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// Run invokes the script named by the context. Scripts which have not been registered fail, unless the tenant permits
+// unregistered scripts.
+func Run(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(c Context) bool {
+	return func(c Context) bool {
+		f, ok := GetRegistry().Get(t.Id(), c.Name())
+		if !ok {
+			if t.PermitUnregisteredScripts() {
+				l.Debugf("Script [%s] of quest %d is not registered, allowing.", c.Name(), c.QuestId())
+				return true
+			}
+			l.Warnf("Script [%s] of quest %d is not registered, refusing.", c.Name(), c.QuestId())
+			return false
+		}
+		return f(l, span, db)(c)
+	}
+}
@@ -0,0 +1,44 @@
+package script
+
+import (
+	"atlas-quest/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"testing"
+)
+
+func TestRunUnregistered(t *testing.T) {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan("test")
+
+	strict := tenant.NewModel("script-strict", "", "", "")
+	permissive := tenant.NewModel("script-permissive", "", "", "", tenant.SetPermitUnregisteredScripts(true))
+	for _, tm := range []tenant.Model{strict, permissive} {
+		GetRegistry().Register(tm.Id(), "registered", func(_ logrus.FieldLogger, _ opentracing.Span, _ *gorm.DB) func(c Context) bool {
+			return func(c Context) bool {
+				return c.Selection() == 1
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		tenant    tenant.Model
+		name      string
+		selection int
+		want      bool
+	}{
+		{strict, "registered", 1, true},
+		{strict, "registered", 0, false},
+		{strict, "unregistered", 1, false},
+		{permissive, "registered", 0, false},
+		{permissive, "unregistered", 1, true},
+	} {
+		got := Run(l, span, nil, tc.tenant)(NewContext(tc.name, 1000, 1, 0, tc.selection))
+		if got != tc.want {
+			t.Errorf("Run(%s) of tenant [%s] with selection %d = %t, want %t", tc.name, tc.tenant.Id(), tc.selection, got, tc.want)
+		}
+	}
+}
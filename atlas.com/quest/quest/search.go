@@ -0,0 +1,157 @@
+package quest
+
+import "strings"
+
+type criteria struct {
+	name         string
+	parent       *string
+	area         *uint32
+	autoStart    *bool
+	repeatable   *bool
+	medal        *bool
+	minLevel     byte
+	maxLevel     byte
+	job          *uint16
+	mob          *uint32
+	rewardItem   *uint32
+	requiredItem *uint32
+	consumedItem *uint32
+}
+
+type Criteria func(c *criteria)
+
+// NameContains matches quests whose name contains value, ignoring case.
+func NameContains(value string) Criteria {
+	return func(c *criteria) {
+		c.name = strings.ToLower(value)
+	}
+}
+
+func HasParent(value string) Criteria {
+	return func(c *criteria) {
+		v := strings.ToLower(value)
+		c.parent = &v
+	}
+}
+
+func InArea(value uint32) Criteria {
+	return func(c *criteria) {
+		c.area = &value
+	}
+}
+
+func IsAutoStart(value bool) Criteria {
+	return func(c *criteria) {
+		c.autoStart = &value
+	}
+}
+
+func IsRepeatable(value bool) Criteria {
+	return func(c *criteria) {
+		c.repeatable = &value
+	}
+}
+
+// AwardsMedal matches quests which do, or do not, award a medal.
+func AwardsMedal(value bool) Criteria {
+	return func(c *criteria) {
+		c.medal = &value
+	}
+}
+
+// StartableWithin matches quests whose level requirements admit some level from minimum to maximum, inclusive. A zero
+// bound leaves the range open on that side.
+func StartableWithin(minimum byte, maximum byte) Criteria {
+	return func(c *criteria) {
+		c.minLevel = minimum
+		c.maxLevel = maximum
+	}
+}
+
+// ForJob matches quests which name the job amongst those permitted to start them.
+func ForJob(value uint16) Criteria {
+	return func(c *criteria) {
+		c.job = &value
+	}
+}
+
+// RelevantTo matches quests which require the monster to be hunted.
+func RelevantTo(mobId uint32) Criteria {
+	return func(c *criteria) {
+		c.mob = &mobId
+	}
+}
+
+func Rewards(itemId uint32) Criteria {
+	return func(c *criteria) {
+		c.rewardItem = &itemId
+	}
+}
+
+func Requires(itemId uint32) Criteria {
+	return func(c *criteria) {
+		c.requiredItem = &itemId
+	}
+}
+
+func Consumes(itemId uint32) Criteria {
+	return func(c *criteria) {
+		c.consumedItem = &itemId
+	}
+}
+
+// candidates narrows the quests to those satisfying the indexed criteria. The ids returned are in ascending order.
+func (i *index) candidates(c *criteria) []uint16 {
+	results := i.ids
+	if c.parent != nil {
+		results = intersect(results, i.byParent[*c.parent])
+	}
+	if c.area != nil {
+		results = intersect(results, i.byArea[*c.area])
+	}
+	if c.job != nil {
+		results = intersect(results, i.byJob[*c.job])
+	}
+	if c.mob != nil {
+		results = intersect(results, i.byMob[*c.mob])
+	}
+	if c.rewardItem != nil {
+		results = intersect(results, i.byRewardItem[*c.rewardItem])
+	}
+	if c.requiredItem != nil {
+		results = intersect(results, i.byRequiredItem[*c.requiredItem])
+	}
+	if c.consumedItem != nil {
+		results = intersect(results, i.byConsumedItem[*c.consumedItem])
+	}
+	if c.name != "" {
+		filtered := make([]uint16, 0)
+		for _, id := range results {
+			if strings.Contains(i.names[id], c.name) {
+				filtered = append(filtered, id)
+			}
+		}
+		results = filtered
+	}
+	return results
+}
+
+// matches applies the criteria which are not indexed.
+func (c *criteria) matches(q Model) bool {
+	if c.autoStart != nil && q.AutoStart() != *c.autoStart {
+		return false
+	}
+	if c.repeatable != nil && q.Repeatable() != *c.repeatable {
+		return false
+	}
+	if c.medal != nil && (q.MedalId() != 0) != *c.medal {
+		return false
+	}
+	if c.maxLevel != 0 && q.MinLevel() > c.maxLevel {
+		return false
+	}
+	if c.minLevel != 0 && q.MaxLevel() != 0 && q.MaxLevel() < c.minLevel {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package sharing
+
+import (
+	"atlas-quest/json"
+	"os"
+)
+
+// configuration is the on disk layout of the kill sharing file. Quests not listed follow the default.
+//
+//	{
+//	  "shared": true,
+//	  "quests": {"2049": false, "6210": false}
+//	}
+type configuration struct {
+	Shared *bool           `json:"shared"`
+	Quests map[uint16]bool `json:"quests"`
+}
+
+func read(path string) (bool, map[uint16]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, nil, err
+	}
+	defer f.Close()
+
+	c := &configuration{}
+	err = json.FromJSON(c, f)
+	if err != nil {
+		return false, nil, err
+	}
+
+	shared := true
+	if c.Shared != nil {
+		shared = *c.Shared
+	}
+	quests := c.Quests
+	if quests == nil {
+		quests = make(map[uint16]bool)
+	}
+	return shared, quests, nil
+}
@@ -0,0 +1,55 @@
+package sharing
+
+import "sync"
+
+// rules is the kill sharing configuration of a single tenant.
+type rules struct {
+	shared bool
+	quests map[uint16]bool
+}
+
+type registry struct {
+	tenants map[string]rules
+	lock    sync.RWMutex
+}
+
+var once sync.Once
+var r *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		r = &registry{
+			tenants: make(map[string]rules),
+			lock:    sync.RWMutex{},
+		}
+	})
+	return r
+}
+
+// Init loads the kill sharing file at path for the tenant, replacing any previously loaded configuration. Without a
+// configuration kills are shared for every quest.
+func (r *registry) Init(tenantId string, path string) error {
+	shared, quests, err := read(path)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tenants[tenantId] = rules{shared: shared, quests: quests}
+	return nil
+}
+
+// IsShared reports whether kills made by a party member count towards the quest.
+func (r *registry) IsShared(tenantId string, questId uint16) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	rs, ok := r.tenants[tenantId]
+	if !ok {
+		return true
+	}
+	if v, ok := rs.quests[questId]; ok {
+		return v
+	}
+	return rs.shared
+}
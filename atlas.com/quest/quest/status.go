@@ -0,0 +1,201 @@
+package quest
+
+import (
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/action"
+	"atlas-quest/quest/requirement"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrAlreadyStarted   = errors.New("quest has already been started")
+	ErrAlreadyCompleted = errors.New("quest has already been completed")
+)
+
+// RequirementError reports the requirements of a quest phase the character has yet to meet.
+type RequirementError struct {
+	QuestId uint16
+	Unmet   []requirement.Type
+}
+
+func (e RequirementError) Error() string {
+	return fmt.Sprintf("requirements of quest %d not met: %s", e.QuestId, strings.Join(requirementNames(e.Unmet), ", "))
+}
+
+// Eligibility is the character's standing against the requirements of both phases of a quest.
+type Eligibility struct {
+	questId       uint16
+	status        string
+	repeatable    bool
+	unmetStart    []requirement.Type
+	unmetComplete []requirement.Type
+}
+
+func (e Eligibility) QuestId() uint16 {
+	return e.questId
+}
+
+// Status is the character's current standing in the quest.
+func (e Eligibility) Status() string {
+	return e.status
+}
+
+// CanStart reports whether the character may start the quest now.
+func (e Eligibility) CanStart() bool {
+	if e.status == characterquest.StatusStarted || (e.status == characterquest.StatusCompleted && !e.repeatable) {
+		return false
+	}
+	return len(e.unmetStart) == 0
+}
+
+// CanComplete reports whether the character may complete the quest now.
+func (e Eligibility) CanComplete() bool {
+	return e.status == characterquest.StatusStarted && len(e.unmetComplete) == 0
+}
+
+// UnmetStart is the starting requirements the character has yet to meet, ordered by type.
+func (e Eligibility) UnmetStart() []requirement.Type {
+	return e.unmetStart
+}
+
+// UnmetComplete is the completion requirements the character has yet to meet, ordered by type.
+func (e Eligibility) UnmetComplete() []requirement.Type {
+	return e.unmetComplete
+}
+
+// GetEligibility evaluates the starting and completion requirements of the quest for the character, speaking with the
+// npc.
+func GetEligibility(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint32, npcId uint32) (Eligibility, error) {
+	return func(characterId uint32, questId uint32, npcId uint32) (Eligibility, error) {
+		q, err := GetById(l, t)(questId)
+		if err != nil {
+			return Eligibility{}, err
+		}
+		cq, err := characterquest.GetById(l, span, db)(characterId, q.Id())
+		if err != nil {
+			return Eligibility{}, err
+		}
+		return Eligibility{
+			questId:       q.Id(),
+			status:        cq.Status(),
+			repeatable:    q.Repeatable(),
+			unmetStart:    evaluate(l, span, db)(characterId, npcId, q.startRequirements),
+			unmetComplete: evaluate(l, span, db)(characterId, npcId, q.completeRequirements),
+		}, nil
+	}
+}
+
+// Start begins the quest for the character, provided it meets the starting requirements of the quest. The starting
+// actions of the quest are run only once the status change is recorded, which fails should a concurrent request have
+// changed the status since it was read.
+func Start(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint32, npcId uint32, selection int) (characterquest.Model, error) {
+	return func(characterId uint32, questId uint32, npcId uint32, selection int) (characterquest.Model, error) {
+		q, err := GetById(l, t)(questId)
+		if err != nil {
+			return characterquest.Model{}, err
+		}
+		cq, err := characterquest.GetById(l, span, db)(characterId, q.Id())
+		if err != nil {
+			return characterquest.Model{}, err
+		}
+		if cq.Status() == characterquest.StatusStarted {
+			return characterquest.Model{}, ErrAlreadyStarted
+		}
+		if cq.Status() == characterquest.StatusCompleted && !q.Repeatable() {
+			return characterquest.Model{}, ErrAlreadyCompleted
+		}
+		if unmet := evaluate(l, span, db)(characterId, npcId, q.startRequirements); len(unmet) > 0 {
+			return characterquest.Model{}, RequirementError{QuestId: q.Id(), Unmet: unmet}
+		}
+
+		cq, err = characterquest.Start(l, db)(characterId, q.Id(), cq.Status())
+		if errors.Is(err, characterquest.ErrStatusChanged) {
+			return characterquest.Model{}, ErrAlreadyStarted
+		}
+		if err != nil {
+			return characterquest.Model{}, err
+		}
+		runActions(l, span, db)(characterId, npcId, selection, q.startActions)
+		return cq, nil
+	}
+}
+
+// Complete finishes the quest the character has in progress, provided it meets the completion requirements of the
+// quest. The completion actions of the quest are run only once the status change is recorded, which fails should a
+// concurrent request have completed or forfeited the quest since it was read.
+func Complete(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB, t tenant.Model) func(characterId uint32, questId uint32, npcId uint32, selection int) (characterquest.Model, error) {
+	return func(characterId uint32, questId uint32, npcId uint32, selection int) (characterquest.Model, error) {
+		q, err := GetById(l, t)(questId)
+		if err != nil {
+			return characterquest.Model{}, err
+		}
+		cq, err := characterquest.GetById(l, span, db)(characterId, q.Id())
+		if err != nil {
+			return characterquest.Model{}, err
+		}
+		if cq.Status() != characterquest.StatusStarted {
+			return characterquest.Model{}, ErrNotStarted
+		}
+		if unmet := evaluate(l, span, db)(characterId, npcId, q.completeRequirements); len(unmet) > 0 {
+			return characterquest.Model{}, RequirementError{QuestId: q.Id(), Unmet: unmet}
+		}
+
+		cq, err = characterquest.Complete(l, db)(characterId, q.Id())
+		if errors.Is(err, characterquest.ErrStatusChanged) {
+			return characterquest.Model{}, ErrNotStarted
+		}
+		if err != nil {
+			return characterquest.Model{}, err
+		}
+		runActions(l, span, db)(characterId, npcId, selection, q.completeActions)
+		return cq, nil
+	}
+}
+
+// evaluate checks each of the requirements, returning those the character does not meet ordered by type.
+func evaluate(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32, requirements map[requirement.Type]requirement.CheckFunc) []requirement.Type {
+	return func(characterId uint32, npcId uint32, requirements map[requirement.Type]requirement.CheckFunc) []requirement.Type {
+		results := make([]requirement.Type, 0)
+		for rt, check := range requirements {
+			if check == nil || !check(l, span, db)(characterId, npcId) {
+				results = append(results, rt)
+			}
+		}
+		sort.Slice(results, func(i, j int) bool {
+			return results[i] < results[j]
+		})
+		return results
+	}
+}
+
+// runActions applies each of the actions whose check the character passes, in order of type.
+func runActions(l logrus.FieldLogger, span opentracing.Span, db *gorm.DB) func(characterId uint32, npcId uint32, selection int, actions map[action.Type]Action) {
+	return func(characterId uint32, npcId uint32, selection int, actions map[action.Type]Action) {
+		types := make([]action.Type, 0, len(actions))
+		for at := range actions {
+			types = append(types, at)
+		}
+		sort.Slice(types, func(i, j int) bool {
+			return types[i] < types[j]
+		})
+
+		for _, at := range types {
+			a := actions[at]
+			if a.run == nil {
+				continue
+			}
+			if a.check != nil && !a.check(l, span, db)(characterId, selection) {
+				l.Debugf("Skipping action [%s] for character %d, as its check was not met.", at, characterId)
+				continue
+			}
+			a.run(l, span, db)(characterId, npcId, selection)
+		}
+	}
+}
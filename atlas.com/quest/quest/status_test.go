@@ -0,0 +1,219 @@
+package quest
+
+import (
+	"atlas-quest/character/inventory"
+	characterquest "atlas-quest/character/quest"
+	"atlas-quest/quest/conversation"
+	"atlas-quest/quest/event"
+	"atlas-quest/quest/requirement"
+	"atlas-quest/quest/script"
+	"atlas-quest/tenant"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeInventory struct {
+	items       map[uint32]uint32
+	failRemoval bool
+}
+
+func (f *fakeInventory) Equipment(_ logrus.FieldLogger, _ opentracing.Span, _ tenant.Model, _ uint32) ([]inventory.Item, error) {
+	return nil, nil
+}
+
+func (f *fakeInventory) Inventory(_ logrus.FieldLogger, _ opentracing.Span, _ tenant.Model, _ uint32, inventoryType string) ([]inventory.Item, error) {
+	results := make([]inventory.Item, 0)
+	for itemId, quantity := range f.items {
+		if inventory.TypeOf(itemId) == inventoryType {
+			results = append(results, inventory.NewItem(itemId, itemId, int16(len(results)+1), quantity))
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeInventory) RemoveItem(_ logrus.FieldLogger, _ opentracing.Span, _ tenant.Model, _ uint32, itemId uint32, quantity uint32) error {
+	if f.failRemoval {
+		return errors.New("inventory unavailable")
+	}
+	f.items[itemId] -= quantity
+	return nil
+}
+
+func openDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migrator := range []func(db *gorm.DB) error{characterquest.Migration, conversation.Migration, event.Migration} {
+		if err = migrator(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func transition(l logrus.FieldLogger, tm tenant.Model, f TransitionFunc, characterId uint32, questId uint32, npcId uint32) int {
+	body := fmt.Sprintf(`{"data":{"type":"quest-transitions","attributes":{"npcId":%d}}}`, npcId)
+	r := httptest.NewRequest(http.MethodPost, "/ms/quest/characters/1/quests/1", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handleTransition(l, tm, f)(characterId, questId)(w, r)
+	return w.Code
+}
+
+func TestStartAndComplete(t *testing.T) {
+	tm := loadQuests(t)
+	db := openDatabase(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(startQuest)
+
+	inv := &fakeInventory{items: make(map[uint32]uint32)}
+	defer inventory.SetClient(inventory.GetClient())
+	inventory.SetClient(inv)
+
+	const (
+		characterId = uint32(1)
+		questId     = uint32(4513)
+		npcId       = uint32(9270030)
+		itemId      = uint32(4000384)
+		mobId       = uint32(9420513)
+	)
+	start := Start(l, span, db, tm)
+	complete := Complete(l, span, db, tm)
+
+	if code := transition(l, tm, start, characterId, 99999, npcId); code != http.StatusNotFound {
+		t.Fatalf("starting an unknown quest = %d, want %d", code, http.StatusNotFound)
+	}
+	_, err := start(characterId, questId, npcId, 0)
+	if re, ok := err.(RequirementError); !ok || len(re.Unmet) != 1 || re.Unmet[0] != requirement.TypeQuest {
+		t.Fatalf("starting before the prerequisites = %v, want unmet %s", err, requirement.TypeQuest)
+	}
+
+	for _, id := range []uint16{4510, 4511, 4512} {
+		if _, err = characterquest.Start(l, db)(characterId, id, characterquest.StatusNotStarted); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = characterquest.Complete(l, db)(characterId, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e, err := GetEligibility(l, span, db, tm)(characterId, questId, npcId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.CanStart() || e.CanComplete() {
+		t.Fatalf("eligibility = start %t complete %t, want start only", e.CanStart(), e.CanComplete())
+	}
+
+	if code := transition(l, tm, start, characterId, questId, 9999999); code != http.StatusConflict {
+		t.Fatalf("starting at the wrong npc = %d, want %d", code, http.StatusConflict)
+	}
+	if code := transition(l, tm, start, characterId, questId, npcId); code != http.StatusCreated {
+		t.Fatalf("starting = %d, want %d", code, http.StatusCreated)
+	}
+	if code := transition(l, tm, start, characterId, questId, npcId); code != http.StatusConflict {
+		t.Fatalf("starting again = %d, want %d", code, http.StatusConflict)
+	}
+
+	ids, err := GetDropEligibility(l, span, db, tm)(characterId, []uint32{itemId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != itemId {
+		t.Fatalf("eligible drops = %v, want [%d]", ids, itemId)
+	}
+	ps, err := GetMobProgress(l, span, db, tm)(characterId, mobId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 1 || ps[0].Count() != 0 || ps[0].Required() != 1 {
+		t.Fatalf("progress = %v, want 0 of 1", ps)
+	}
+
+	_, err = complete(characterId, questId, npcId, 0)
+	if re, ok := err.(RequirementError); !ok || len(re.Unmet) != 2 {
+		t.Fatalf("completing without the item and kill = %v, want two unmet requirements", err)
+	}
+
+	ps, err = RecordKill(l, span, db, tm)(characterId, mobId, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 1 || ps[0].Count() != 1 {
+		t.Fatalf("progress = %v, want 1 of 1", ps)
+	}
+	inv.items[itemId] = 1
+	e, err = GetEligibility(l, span, db, tm)(characterId, questId, npcId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.CanStart() || !e.CanComplete() {
+		t.Fatalf("eligibility = start %t complete %t, want complete only", e.CanStart(), e.CanComplete())
+	}
+
+	if code := transition(l, tm, complete, characterId, questId, npcId); code != http.StatusCreated {
+		t.Fatalf("completing = %d, want %d", code, http.StatusCreated)
+	}
+	cq, err := characterquest.GetById(l, span, db)(characterId, uint16(questId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cq.Status() != characterquest.StatusCompleted {
+		t.Fatalf("status = %s, want %s", cq.Status(), characterquest.StatusCompleted)
+	}
+	if code := transition(l, tm, start, characterId, questId, npcId); code != http.StatusConflict {
+		t.Fatalf("restarting = %d, want %d", code, http.StatusConflict)
+	}
+	if code := transition(l, tm, complete, characterId, questId, npcId); code != http.StatusConflict {
+		t.Fatalf("completing again = %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestCompleteRunsEndScript(t *testing.T) {
+	tm := loadQuests(t)
+	db := openDatabase(t)
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan(completeQuest)
+
+	const (
+		characterId = uint32(1)
+		questId     = uint16(10037)
+		npcId       = uint32(9010010)
+	)
+	var contexts []script.Context
+	script.GetRegistry().Register(tm.Id(), "q10037e", func(_ logrus.FieldLogger, _ opentracing.Span, _ *gorm.DB) func(c script.Context) bool {
+		return func(c script.Context) bool {
+			contexts = append(contexts, c)
+			return true
+		}
+	})
+
+	if _, err := characterquest.Start(l, db)(characterId, questId, characterquest.StatusNotStarted); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Complete(l, span, db, tm)(characterId, uint32(questId), npcId, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(contexts) != 2 {
+		t.Fatalf("script invoked %d times, want 2", len(contexts))
+	}
+	if contexts[0].Action() {
+		t.Errorf("first invocation checked the requirements, want it not to act")
+	}
+	if !contexts[1].Action() || contexts[1].Selection() != 2 || contexts[1].NpcId() != npcId {
+		t.Errorf("action invocation = action %t selection %d npc %d, want action with selection 2 at npc %d", contexts[1].Action(), contexts[1].Selection(), contexts[1].NpcId(), npcId)
+	}
+}
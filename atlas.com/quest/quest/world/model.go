@@ -0,0 +1,37 @@
+package world
+
+// Model is the quest availability of a single world. Quests listed as enabled are available regardless of the
+// service-wide defaults, whereas those listed as disabled are not.
+type Model struct {
+	id       byte
+	enabled  map[uint16]bool
+	disabled map[uint16]bool
+}
+
+func (m Model) Id() byte {
+	return m.id
+}
+
+func (m Model) Enabled() []uint16 {
+	return keys(m.enabled)
+}
+
+func (m Model) Disabled() []uint16 {
+	return keys(m.disabled)
+}
+
+func keys(s map[uint16]bool) []uint16 {
+	results := make([]uint16, 0, len(s))
+	for id := range s {
+		results = append(results, id)
+	}
+	return results
+}
+
+func makeSet(ids []uint16) map[uint16]bool {
+	results := make(map[uint16]bool, len(ids))
+	for _, id := range ids {
+		results[id] = true
+	}
+	return results
+}
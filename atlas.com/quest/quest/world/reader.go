@@ -0,0 +1,49 @@
+package world
+
+import (
+	"atlas-quest/json"
+	"os"
+)
+
+// configuration is the on disk layout of the world availability file.
+//
+//	{
+//	  "disabled": [2000],
+//	  "worlds": [
+//	    {"id": 1, "enabled": [2000], "disabled": [4500, 4501]}
+//	  ]
+//	}
+type configuration struct {
+	Disabled []uint16             `json:"disabled"`
+	Worlds   []worldConfiguration `json:"worlds"`
+}
+
+type worldConfiguration struct {
+	Id       byte     `json:"id"`
+	Enabled  []uint16 `json:"enabled"`
+	Disabled []uint16 `json:"disabled"`
+}
+
+func read(path string) (map[uint16]bool, map[byte]Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	c := &configuration{}
+	err = json.FromJSON(c, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	worlds := make(map[byte]Model)
+	for _, wc := range c.Worlds {
+		worlds[wc.Id] = Model{
+			id:       wc.Id,
+			enabled:  makeSet(wc.Enabled),
+			disabled: makeSet(wc.Disabled),
+		}
+	}
+	return makeSet(c.Disabled), worlds, nil
+}
@@ -0,0 +1,81 @@
+package world
+
+import "sync"
+
+// availability is the world configuration of a single tenant.
+type availability struct {
+	disabled map[uint16]bool
+	worlds   map[byte]Model
+}
+
+type registry struct {
+	tenants map[string]availability
+	lock    sync.RWMutex
+}
+
+var once sync.Once
+var r *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		r = &registry{
+			tenants: make(map[string]availability),
+			lock:    sync.RWMutex{},
+		}
+	})
+	return r
+}
+
+// Init loads the world availability file at path for the tenant, replacing any previously loaded configuration.
+// Without a configuration every quest is available in every world.
+func (r *registry) Init(tenantId string, path string) error {
+	disabled, worlds, err := read(path)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tenants[tenantId] = availability{disabled: disabled, worlds: worlds}
+	return nil
+}
+
+func (r *registry) Get(tenantId string, worldId byte) (Model, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	m, ok := r.tenants[tenantId].worlds[worldId]
+	return m, ok
+}
+
+// IsConfigured reports whether the availability of the quest differs between worlds, or is disabled outright.
+func (r *registry) IsConfigured(tenantId string, questId uint16) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	a := r.tenants[tenantId]
+	if a.disabled[questId] {
+		return true
+	}
+	for _, m := range a.worlds {
+		if m.enabled[questId] || m.disabled[questId] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAvailable reports whether the quest may be undertaken in the world. World specific entries take precedence over
+// the service-wide disabled list.
+func (r *registry) IsAvailable(tenantId string, worldId byte, questId uint16) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	a := r.tenants[tenantId]
+	if m, ok := a.worlds[worldId]; ok {
+		if m.disabled[questId] {
+			return false
+		}
+		if m.enabled[questId] {
+			return true
+		}
+	}
+	return !a.disabled[questId]
+}
@@ -0,0 +1,37 @@
+package world
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistryIsolatesTenants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worlds.json")
+	err := os.WriteFile(path, []byte(`{"disabled": [2000], "worlds": [{"id": 1, "enabled": [2000]}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = GetRegistry().Init("configured", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		tenantId string
+		worldId  byte
+		want     bool
+	}{
+		{"configured", 0, false},
+		{"configured", 1, true},
+		{"other", 0, true},
+		{"other", 1, true},
+	} {
+		if got := GetRegistry().IsAvailable(tc.tenantId, tc.worldId, 2000); got != tc.want {
+			t.Errorf("IsAvailable(%s, %d, 2000) = %v, want %v", tc.tenantId, tc.worldId, got, tc.want)
+		}
+	}
+	if GetRegistry().IsConfigured("other", 2000) {
+		t.Errorf("IsConfigured(other, 2000) = true, want false")
+	}
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var adminToken string
+var adminTokenLock sync.RWMutex
+
+// SetAdminToken sets the bearer token administrative routes require. Until one is set, administrative routes refuse all
+// requests.
+func SetAdminToken(token string) {
+	adminTokenLock.Lock()
+	defer adminTokenLock.Unlock()
+	adminToken = token
+}
+
+// RequireAdmin serves the request only when it carries the administrative bearer token.
+func RequireAdmin(l logrus.FieldLogger, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adminTokenLock.RLock()
+		token := adminToken
+		adminTokenLock.RUnlock()
+
+		if token == "" {
+			l.Warnf("Refusing administrative request to [%s], no admin token is configured.", r.URL.Path)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			l.Warnf("Refusing unauthorized administrative request to [%s].", r.URL.Path)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	h := RequireAdmin(l, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, tc := range []struct {
+		token         string
+		authorization string
+		want          int
+	}{
+		{"", "", http.StatusForbidden},
+		{"", "Bearer ", http.StatusForbidden},
+		{"secret", "", http.StatusUnauthorized},
+		{"secret", "secret", http.StatusUnauthorized},
+		{"secret", "Bearer wrong", http.StatusUnauthorized},
+		{"secret", "Bearer secret", http.StatusNoContent},
+	} {
+		SetAdminToken(tc.token)
+		r := httptest.NewRequest(http.MethodDelete, "/ms/quest/quests/", nil)
+		if tc.authorization != "" {
+			r.Header.Set("Authorization", tc.authorization)
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		if w.Code != tc.want {
+			t.Errorf("token [%s] with authorization [%s] = %d, want %d", tc.token, tc.authorization, w.Code, tc.want)
+		}
+	}
+	SetAdminToken("")
+}
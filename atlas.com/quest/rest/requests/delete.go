@@ -3,13 +3,20 @@ package requests
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"net/http"
 )
 
-func Delete(l logrus.FieldLogger, span opentracing.Span) func(url string, input interface{}) error {
-	return func(url string, input interface{}) error {
+func Delete(l logrus.FieldLogger, span opentracing.Span) func(url string, input interface{}, configurators ...Configurator) error {
+	return func(url string, input interface{}, configurators ...Configurator) error {
+		c := &configuration{}
+		for _, configurator := range configurators {
+			configurator(c)
+		}
+
 		jsonReq, err := json.Marshal(input)
 		if err != nil {
 			return err
@@ -20,6 +27,9 @@ func Delete(l logrus.FieldLogger, span opentracing.Span) func(url string, input
 			return err
 		}
 		req.Header.Set("Content-Type", "application/json")
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
 		err = opentracing.GlobalTracer().Inject(
 			span.Context(),
 			opentracing.HTTPHeaders,
@@ -28,9 +38,14 @@ func Delete(l logrus.FieldLogger, span opentracing.Span) func(url string, input
 			l.WithError(err).Errorf("Unable to decorate request headers with OpenTracing information.")
 		}
 		r, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
 
 		l.WithFields(logrus.Fields{"method": http.MethodDelete, "status": r.Status, "path": url, "input": input, "response": ""}).Debugf("Printing request.")
-
-		return err
+		if r.StatusCode >= http.StatusBadRequest {
+			return errors.New(fmt.Sprintf("unable to delete %s: %s", url, r.Status))
+		}
+		return nil
 	}
 }
@@ -26,6 +26,9 @@ func get(l logrus.FieldLogger, span opentracing.Span) func(url string, resp inte
 				return true, err
 			}
 			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
 			err = opentracing.GlobalTracer().Inject(
 				span.Context(),
 				opentracing.HTTPHeaders,
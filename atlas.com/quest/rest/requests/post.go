@@ -10,8 +10,13 @@ import (
 
 type PostRequest[A any] func(l logrus.FieldLogger, span opentracing.Span) (DataContainer[A], ErrorListDataContainer, error)
 
-func post(l logrus.FieldLogger, span opentracing.Span) func(url string, input interface{}, resp interface{}, errResp *ErrorListDataContainer) error {
-	return func(url string, input interface{}, resp interface{}, errResp *ErrorListDataContainer) error {
+func post(l logrus.FieldLogger, span opentracing.Span) func(url string, input interface{}, resp interface{}, errResp *ErrorListDataContainer, configurators ...Configurator) error {
+	return func(url string, input interface{}, resp interface{}, errResp *ErrorListDataContainer, configurators ...Configurator) error {
+		c := &configuration{}
+		for _, configurator := range configurators {
+			configurator(c)
+		}
+
 		jsonReq, err := json.Marshal(input)
 		if err != nil {
 			return err
@@ -22,6 +27,9 @@ func post(l logrus.FieldLogger, span opentracing.Span) func(url string, input in
 			return err
 		}
 		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
 		err = opentracing.GlobalTracer().Inject(
 			span.Context(),
 			opentracing.HTTPHeaders,
@@ -69,7 +77,7 @@ func MakePostRequest[A any](url string, i interface{}, configurators ...Configur
 		r := dataContainer[A]{includedMappers: c.mappers}
 		errResp := ErrorListDataContainer{}
 
-		err := post(l, span)(url, i, &r, &errResp)
+		err := post(l, span)(url, i, &r, &errResp, configurators...)
 		return r, errResp, err
 	}
 }
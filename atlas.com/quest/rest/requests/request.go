@@ -11,6 +11,7 @@ const (
 type configuration struct {
 	retries int
 	mappers []response.ConditionalMapperProvider
+	headers map[string]string
 }
 
 type Configurator func(c *configuration)
@@ -32,3 +33,12 @@ func AddMapper(mapper response.ConditionalMapperProvider) Configurator {
 		c.mappers = append(c.mappers, mapper)
 	}
 }
+
+func SetHeader(key string, value string) Configurator {
+	return func(c *configuration) {
+		if c.headers == nil {
+			c.headers = make(map[string]string)
+		}
+		c.headers[key] = value
+	}
+}
@@ -0,0 +1,48 @@
+package requests
+
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeadersForwarded(t *testing.T) {
+	received := make(map[string]string)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received[r.Method] = r.Header.Get("TENANT_ID")
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"id":"1","type":"tests","attributes":{}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	span := opentracing.NoopTracer{}.StartSpan("test")
+	header := SetHeader("TENANT_ID", "gms-83")
+
+	_, err := MakeGetRequest[struct{}](s.URL, header)(l, span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = MakePostRequest[struct{}](s.URL, struct{}{}, header)(l, span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Delete(l, span)(s.URL, struct{}{}, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		if received[method] != "gms-83" {
+			t.Errorf("%s sent tenant [%s], want gms-83", method, received[method])
+		}
+	}
+}
@@ -55,8 +55,8 @@ func NewServer(cl *logrus.Logger, ctx context.Context, wg *sync.WaitGroup, route
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	wg.Add(1)
 	go func() {
-		wg.Add(1)
 		defer wg.Done()
 		err := hs.ListenAndServe()
 		if err != http.ErrServerClosed {
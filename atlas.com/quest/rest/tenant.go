@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"atlas-quest/tenant"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type TenantHandler func(t tenant.Model) http.HandlerFunc
+
+// ParseTenant resolves the tenant named by the request header. Requests which do not name one are served for the
+// default tenant.
+func ParseTenant(l logrus.FieldLogger, next TenantHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(tenant.HeaderKey)
+		if id == "" {
+			id = tenant.DefaultId
+		}
+		t, err := tenant.GetRegistry().Get(id)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to locate tenant [%s].", id)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(t)(w, r)
+	}
+}
@@ -0,0 +1,111 @@
+package tenant
+
+import "gorm.io/gorm"
+
+// DefaultId identifies the tenant requests are served for when they do not name one.
+const DefaultId = "default"
+
+// HeaderKey is the request header naming the tenant a request is served for.
+const HeaderKey = "TENANT_ID"
+
+// Model is a game version or region served by the deployment. Each tenant reads its own WZ directory and keeps
+// character progress in its own database.
+type Model struct {
+	id                string
+	wzDir             string
+	snapshotPath      string
+	databaseName      string
+	worldConfig       string
+	killSharingConfig string
+	forfeitConfig     string
+	permitScripts     bool
+}
+
+type Configurator func(m *Model)
+
+// SetWorldConfig names the world availability file of the tenant.
+func SetWorldConfig(path string) Configurator {
+	return func(m *Model) {
+		m.worldConfig = path
+	}
+}
+
+// SetKillSharingConfig names the quest kill sharing file of the tenant.
+func SetKillSharingConfig(path string) Configurator {
+	return func(m *Model) {
+		m.killSharingConfig = path
+	}
+}
+
+// SetForfeitConfig names the quest forfeit rules file of the tenant.
+func SetForfeitConfig(path string) Configurator {
+	return func(m *Model) {
+		m.forfeitConfig = path
+	}
+}
+
+// SetPermitUnregisteredScripts allows quest scripts with no registered hook to pass for the tenant. Otherwise they fail,
+// leaving the quests which depend on them unavailable.
+func SetPermitUnregisteredScripts(permit bool) Configurator {
+	return func(m *Model) {
+		m.permitScripts = permit
+	}
+}
+
+func NewModel(id string, wzDir string, snapshotPath string, databaseName string, configurators ...Configurator) Model {
+	m := Model{
+		id:           id,
+		wzDir:        wzDir,
+		snapshotPath: snapshotPath,
+		databaseName: databaseName,
+	}
+	for _, configurator := range configurators {
+		configurator(&m)
+	}
+	return m
+}
+
+func (m Model) Id() string {
+	return m.id
+}
+
+func (m Model) WzDir() string {
+	return m.wzDir
+}
+
+// SnapshotPath is the precompiled WZ snapshot to serve the tenant from, if any.
+func (m Model) SnapshotPath() string {
+	return m.snapshotPath
+}
+
+// DatabaseName is the database holding the tenant's character progress. Tenants read from the tenant file always name
+// one. Only the default tenant has none, and uses the service database.
+func (m Model) DatabaseName() string {
+	return m.databaseName
+}
+
+// WorldConfig is the world availability file of the tenant. When empty the service default is used.
+func (m Model) WorldConfig() string {
+	return m.worldConfig
+}
+
+// KillSharingConfig is the quest kill sharing file of the tenant. When empty the service default is used.
+func (m Model) KillSharingConfig() string {
+	return m.killSharingConfig
+}
+
+// ForfeitConfig is the quest forfeit rules file of the tenant. When empty the service default is used.
+func (m Model) ForfeitConfig() string {
+	return m.forfeitConfig
+}
+
+// PermitUnregisteredScripts reports whether quest scripts with no registered hook pass for the tenant.
+func (m Model) PermitUnregisteredScripts() bool {
+	return m.permitScripts
+}
+
+// Database returns the connection holding the tenant's character progress, or fallback, the service database, for the
+// default tenant.
+func (m Model) Database(fallback *gorm.DB) *gorm.DB {
+	return GetRegistry().Database(m.id, fallback)
+}
@@ -0,0 +1,70 @@
+package tenant
+
+import (
+	"atlas-quest/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// configuration is the on disk layout of the tenant file.
+//
+//	{
+//	  "tenants": [
+//	    {"id": "gms-83", "wzDir": "/wz/83", "snapshot": "/wz/83.snap", "database": "atlas_quest_83", "worldConfig": "/config/83/worlds.json"},
+//	    {"id": "gms-95", "wzDir": "/wz/95", "database": "atlas_quest_95"}
+//	  ]
+//	}
+type configuration struct {
+	Tenants []tenantConfiguration `json:"tenants"`
+}
+
+type tenantConfiguration struct {
+	Id       string `json:"id"`
+	WzDir    string `json:"wzDir"`
+	Snapshot string `json:"snapshot"`
+	Database string `json:"database"`
+	// WorldConfig, KillSharingConfig and ForfeitConfig override the service-wide quest rule files for the tenant.
+	WorldConfig       string `json:"worldConfig"`
+	KillSharingConfig string `json:"killSharingConfig"`
+	ForfeitConfig     string `json:"forfeitConfig"`
+	// PermitUnregisteredScripts lets quest scripts with no registered hook pass, rather than fail.
+	PermitUnregisteredScripts bool `json:"permitUnregisteredScripts"`
+}
+
+func read(path string) ([]Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &configuration{}
+	err = json.FromJSON(c, f)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Model, 0)
+	ids := make(map[string]bool)
+	databases := make(map[string]string)
+	for _, tc := range c.Tenants {
+		if tc.Id == "" || tc.WzDir == "" {
+			return nil, errors.New(fmt.Sprintf("tenant [%s] requires both an id and a WZ directory", tc.Id))
+		}
+		// character progress is not keyed by tenant, so each tenant must keep it in a database of its own.
+		if tc.Database == "" {
+			return nil, errors.New(fmt.Sprintf("tenant [%s] requires a database", tc.Id))
+		}
+		if ids[tc.Id] {
+			return nil, errors.New(fmt.Sprintf("tenant [%s] is listed more than once", tc.Id))
+		}
+		if other, ok := databases[tc.Database]; ok {
+			return nil, errors.New(fmt.Sprintf("tenants [%s] and [%s] share database [%s]", other, tc.Id, tc.Database))
+		}
+		ids[tc.Id] = true
+		databases[tc.Database] = tc.Id
+		results = append(results, NewModel(tc.Id, tc.WzDir, tc.Snapshot, tc.Database, SetWorldConfig(tc.WorldConfig), SetKillSharingConfig(tc.KillSharingConfig), SetForfeitConfig(tc.ForfeitConfig), SetPermitUnregisteredScripts(tc.PermitUnregisteredScripts)))
+	}
+	return results, nil
+}
@@ -0,0 +1,32 @@
+package tenant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadRequiresSeparateDatabases(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config string
+		ok     bool
+	}{
+		{"separate", `{"tenants": [{"id": "a", "wzDir": "/wz/a", "database": "quest_a"}, {"id": "b", "wzDir": "/wz/b", "database": "quest_b"}]}`, true},
+		{"missing database", `{"tenants": [{"id": "a", "wzDir": "/wz/a"}]}`, false},
+		{"shared database", `{"tenants": [{"id": "a", "wzDir": "/wz/a", "database": "quest"}, {"id": "b", "wzDir": "/wz/b", "database": "quest"}]}`, false},
+		{"duplicate id", `{"tenants": [{"id": "a", "wzDir": "/wz/a", "database": "quest_a"}, {"id": "a", "wzDir": "/wz/b", "database": "quest_b"}]}`, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tenants.json")
+			err := os.WriteFile(path, []byte(tc.config), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = read(path)
+			if (err == nil) != tc.ok {
+				t.Errorf("read() error = %v, want ok %t", err, tc.ok)
+			}
+		})
+	}
+}
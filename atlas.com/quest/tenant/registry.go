@@ -0,0 +1,87 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"sync"
+)
+
+type registry struct {
+	tenants   map[string]Model
+	databases map[string]*gorm.DB
+	lock      sync.RWMutex
+}
+
+var once sync.Once
+var r *registry
+
+func GetRegistry() *registry {
+	once.Do(func() {
+		r = &registry{
+			tenants:   make(map[string]Model),
+			databases: make(map[string]*gorm.DB),
+			lock:      sync.RWMutex{},
+		}
+	})
+	return r
+}
+
+// Init loads the tenants listed in the file at path, in addition to any already registered.
+func (r *registry) Init(path string) error {
+	ts, err := read(path)
+	if err != nil {
+		return err
+	}
+	for _, t := range ts {
+		r.Add(t)
+	}
+	return nil
+}
+
+func (r *registry) Add(t Model) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tenants[t.Id()] = t
+}
+
+func (r *registry) Get(id string) (Model, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if t, ok := r.tenants[id]; ok {
+		return t, nil
+	}
+	return Model{}, errors.New(fmt.Sprintf("tenant %s not found", id))
+}
+
+func (r *registry) GetAll() []Model {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	results := make([]Model, 0, len(r.tenants))
+	for _, t := range r.tenants {
+		results = append(results, t)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Id() < results[j].Id()
+	})
+	return results
+}
+
+// SetDatabase records the connection holding the tenant's character progress.
+func (r *registry) SetDatabase(id string, db *gorm.DB) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.databases[id] = db
+}
+
+// Database returns the connection holding the tenant's character progress, or fallback, the service database, for the
+// default tenant. Only the default tenant is without a database of its own.
+func (r *registry) Database(id string, fallback *gorm.DB) *gorm.DB {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if db, ok := r.databases[id]; ok {
+		return db
+	}
+	return fallback
+}
@@ -1,13 +1,14 @@
 package topic
 
 import (
+	"atlas-quest/tenant"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"sync"
 )
 
 type registry struct {
-	topics map[string]string
+	topics map[string]map[string]string
 	lock   sync.RWMutex
 }
 
@@ -17,26 +18,27 @@ var r *registry
 func GetRegistry() *registry {
 	once.Do(func() {
 		r = &registry{
-			topics: make(map[string]string, 0),
+			topics: make(map[string]map[string]string),
 			lock:   sync.RWMutex{},
 		}
 	})
 	return r
 }
 
-func (r *registry) Get(l logrus.FieldLogger, span opentracing.Span, token string) string {
+// Get resolves the topic name of the token for the tenant, caching it for subsequent calls.
+func (r *registry) Get(l logrus.FieldLogger, span opentracing.Span, t tenant.Model, token string) string {
 	r.lock.RLock()
-	if val, ok := r.topics[token]; ok {
+	if val, ok := r.topics[t.Id()][token]; ok {
 		r.lock.RUnlock()
 		return val
 	} else {
 		r.lock.RUnlock()
 		r.lock.Lock()
-		if val, ok = r.topics[token]; ok {
+		if val, ok = r.topics[t.Id()][token]; ok {
 			r.lock.Unlock()
 			return val
 		}
-		td, err := getTopic(token)(l, span)
+		td, err := getTopic(t, token)(l, span)
 		if err != nil {
 			r.lock.Unlock()
 			l.WithError(err).Fatalf("Unable to locate topic for token %s.", token)
@@ -44,7 +46,10 @@ func (r *registry) Get(l logrus.FieldLogger, span opentracing.Span, token string
 		}
 		attr := td.Data().Attributes
 
-		r.topics[token] = attr.Name
+		if _, ok := r.topics[t.Id()]; !ok {
+			r.topics[t.Id()] = make(map[string]string)
+		}
+		r.topics[t.Id()][token] = attr.Name
 		r.lock.Unlock()
 		return attr.Name
 	}
@@ -2,6 +2,7 @@ package topic
 
 import (
 	"atlas-quest/rest/requests"
+	"atlas-quest/tenant"
 	"fmt"
 )
 
@@ -11,6 +12,6 @@ const (
 	topicById                  = topicsService + "topics/%s"
 )
 
-func getTopic(topic string) requests.Request[attributes] {
-	return requests.MakeGetRequest[attributes](fmt.Sprintf(topicById, topic), requests.SetRetries(10))
+func getTopic(t tenant.Model, topic string) requests.Request[attributes] {
+	return requests.MakeGetRequest[attributes](fmt.Sprintf(topicById, topic), requests.SetRetries(10), requests.SetHeader(tenant.HeaderKey, t.Id()))
 }
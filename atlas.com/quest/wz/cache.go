@@ -1,39 +1,155 @@
 package wz
 
 import (
+	"atlas-quest/xml"
 	"errors"
 	"fmt"
 	"sync"
 )
 
 type fileCache struct {
-	files map[string]FileEntry
+	path         string
+	files        map[string]FileEntry
+	snapshotPath string
+	snapshot     *Snapshot
+	lock         sync.RWMutex
 }
 
-var cache *fileCache
-var once sync.Once
+var caches = make(map[string]*fileCache)
+var cachesLock sync.Mutex
 
-func GetFileCache() *fileCache {
-	return cache
+// GetFileCache returns the file cache of the tenant, creating an empty one should it not yet exist.
+func GetFileCache(tenantId string) *fileCache {
+	cachesLock.Lock()
+	defer cachesLock.Unlock()
+	if c, ok := caches[tenantId]; ok {
+		return c
+	}
+	c := &fileCache{files: make(map[string]FileEntry)}
+	caches[tenantId] = c
+	return c
 }
 
 func (e *fileCache) Init(wzPath string) {
-	once.Do(func() {
-		es, err := Read(wzPath)
+	e.lock.Lock()
+	e.path = wzPath
+	e.lock.Unlock()
+
+	err := e.Refresh()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Refresh re-reads the file listing of the configured directory. If a snapshot is in use, it is re-read and dropped
+// should it no longer match the XML content.
+func (e *fileCache) Refresh() error {
+	e.lock.RLock()
+	path := e.path
+	snapshotPath := e.snapshotPath
+	e.lock.RUnlock()
+
+	es, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	var files = make(map[string]FileEntry)
+	for _, e := range es {
+		files[e.Name()] = e
+	}
+
+	var s *Snapshot
+	if snapshotPath != "" {
+		s, _ = loadSnapshot(snapshotPath, es)
+	}
+
+	e.lock.Lock()
+	e.files = files
+	e.snapshot = s
+	e.lock.Unlock()
+	return nil
+}
+
+// UseSnapshot serves node trees from the precompiled snapshot at path, rather than parsing the XML files. The snapshot
+// is rejected if the directory contains XML which it was not compiled from.
+func (e *fileCache) UseSnapshot(path string) error {
+	e.lock.RLock()
+	wzPath := e.path
+	e.lock.RUnlock()
+
+	var es []FileEntry
+	if wzPath != "" {
+		var err error
+		es, err = Read(wzPath)
 		if err != nil {
-			panic(err)
+			return err
 		}
+	}
 
-		var files = make(map[string]FileEntry)
-		for _, e := range es {
-			files[e.Name()] = e
+	s, err := loadSnapshot(path, es)
+	e.lock.Lock()
+	e.snapshotPath = path
+	e.snapshot = s
+	e.lock.Unlock()
+	return err
+}
+
+func loadSnapshot(path string, es []FileEntry) (*Snapshot, error) {
+	s, err := ReadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	xes := make([]FileEntry, 0)
+	for _, e := range es {
+		if isXML(e) {
+			xes = append(xes, e)
 		}
+	}
+	if len(xes) == 0 {
+		return s, nil
+	}
+
+	current, err := s.Current(xes)
+	if err != nil {
+		return nil, err
+	}
+	if !current {
+		return nil, errors.New(fmt.Sprintf("snapshot %s is stale", path))
+	}
+	return s, nil
+}
+
+// Snapshot returns the snapshot currently in use, if any.
+func (e *fileCache) Snapshot() (*Snapshot, bool) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.snapshot, e.snapshot != nil
+}
+
+// GetNode returns the parsed contents of the named file, preferring the snapshot when one is in use.
+func (e *fileCache) GetNode(name string) (*xml.Node, error) {
+	if s, ok := e.Snapshot(); ok && s.Has(name) {
+		return s.File(name)
+	}
+
+	fe, err := e.GetFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return xml.Read(fe.Path())
+}
 
-		cache = &fileCache{files: files}
-	})
+func (e *fileCache) Path() string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.path
 }
 
 func (e *fileCache) GetFile(name string) (*FileEntry, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
 	if val, ok := e.files[name]; ok {
 		return &val, nil
 	} else {
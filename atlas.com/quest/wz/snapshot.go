@@ -0,0 +1,358 @@
+package wz
+
+import (
+	"atlas-quest/xml"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	snapshotMagic   = "AQSN"
+	snapshotVersion = 3
+)
+
+// Snapshot is a precompiled form of the WZ XML files in a directory, holding the node tree of each file in a binary
+// encoding which is cheaper to decode than XML. Only an index of the files is kept in memory; each tree is decoded
+// from disk when requested, and released once the caller is done with it. Along with the trees, the snapshot records a
+// hash of the XML content it was compiled from, and the size and modification time of each file, so a stale snapshot
+// can be detected.
+//
+// The snapshot holds node trees, not quest models. It spares the service the XML parse, which is the bulk of a cold
+// start, but the requirement and action closures of every quest are still built from the trees on each load.
+type Snapshot struct {
+	path    string
+	version uint64
+	hash    string
+	stamps  map[string]fileStamp
+	files   map[string]snapshotEntry
+}
+
+// fileStamp is the size and modification time of an XML file, which are checked before resorting to a content hash.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+func stampOf(e FileEntry) (fileStamp, error) {
+	stat, err := os.Stat(e.Path())
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{size: stat.Size(), modTime: stat.ModTime().UnixNano()}, nil
+}
+
+// snapshotEntry locates the encoded tree of a file within the snapshot.
+type snapshotEntry struct {
+	offset int64
+	length int64
+}
+
+func (s Snapshot) Version() uint64 {
+	return s.version
+}
+
+func (s Snapshot) Hash() string {
+	return s.hash
+}
+
+// Has reports whether the snapshot holds the named file.
+func (s Snapshot) Has(name string) bool {
+	_, ok := s.files[name]
+	return ok
+}
+
+// Current reports whether the snapshot was compiled from the XML files provided. When each file has the size and
+// modification time recorded at compile time, the files are taken as unchanged without being read. Otherwise the
+// content hash of the files is compared.
+func (s Snapshot) Current(entries []FileEntry) (bool, error) {
+	xes := make([]FileEntry, 0)
+	for _, e := range entries {
+		if isXML(e) {
+			xes = append(xes, e)
+		}
+	}
+
+	unchanged := len(xes) == len(s.stamps)
+	for _, e := range xes {
+		if !unchanged {
+			break
+		}
+		want, ok := s.stamps[e.Name()]
+		got, err := stampOf(e)
+		unchanged = ok && err == nil && got == want
+	}
+	if unchanged {
+		return true, nil
+	}
+
+	hash, err := ContentHash(xes)
+	if err != nil {
+		return false, err
+	}
+	return hash == s.hash, nil
+}
+
+// File decodes the node tree of the named file.
+func (s Snapshot) File(name string) (*xml.Node, error) {
+	e, ok := s.files[name]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("file %s not found in snapshot", name))
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return xml.ReadBinary(bufio.NewReader(io.NewSectionReader(f, e.offset, e.length)))
+}
+
+func isXML(e FileEntry) bool {
+	return strings.HasSuffix(e.Name(), ".xml")
+}
+
+// ContentHash produces a hash of the XML files provided, independent of the order they are supplied in.
+func ContentHash(entries []FileEntry) (string, error) {
+	xes := make([]FileEntry, 0)
+	for _, e := range entries {
+		if isXML(e) {
+			xes = append(xes, e)
+		}
+	}
+	sort.Slice(xes, func(i, j int) bool {
+		return xes[i].Name() < xes[j].Name()
+	})
+
+	h := sha256.New()
+	for _, e := range xes {
+		_, err := io.WriteString(h, e.Name()+"\x00")
+		if err != nil {
+			return "", err
+		}
+		f, err := os.Open(e.Path())
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Compile parses every XML file found beneath path, and writes their node trees to w as a snapshot. The content hash of
+// the compiled files is returned.
+func Compile(path string, w io.Writer) (string, error) {
+	es, err := Read(path)
+	if err != nil {
+		return "", err
+	}
+
+	xes := make([]FileEntry, 0)
+	for _, e := range es {
+		if isXML(e) {
+			xes = append(xes, e)
+		}
+	}
+	// stamps are taken ahead of the hash, so a file modified while compiling fails the cheaper check.
+	stamps := make([]fileStamp, 0, len(xes))
+	for _, e := range xes {
+		stamp, err := stampOf(e)
+		if err != nil {
+			return "", err
+		}
+		stamps = append(stamps, stamp)
+	}
+	hash, err := ContentHash(es)
+	if err != nil {
+		return "", err
+	}
+
+	bw := bufio.NewWriter(w)
+	_, err = bw.WriteString(snapshotMagic)
+	if err != nil {
+		return "", err
+	}
+	err = writeUvarint(bw, snapshotVersion)
+	if err != nil {
+		return "", err
+	}
+	err = writeString(bw, hash)
+	if err != nil {
+		return "", err
+	}
+	err = writeUvarint(bw, uint64(len(xes)))
+	if err != nil {
+		return "", err
+	}
+	for i, e := range xes {
+		err = writeString(bw, e.Name())
+		if err != nil {
+			return "", err
+		}
+		err = writeUvarint(bw, uint64(stamps[i].size))
+		if err != nil {
+			return "", err
+		}
+		err = writeUvarint(bw, uint64(stamps[i].modTime))
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, e := range xes {
+		n, err := xml.Read(e.Path())
+		if err != nil {
+			return "", err
+		}
+		err = writeString(bw, e.Name())
+		if err != nil {
+			return "", err
+		}
+		encoded := &bytes.Buffer{}
+		err = xml.WriteBinary(encoded, n)
+		if err != nil {
+			return "", err
+		}
+		err = writeUvarint(bw, uint64(encoded.Len()))
+		if err != nil {
+			return "", err
+		}
+		_, err = bw.Write(encoded.Bytes())
+		if err != nil {
+			return "", err
+		}
+	}
+	return hash, bw.Flush()
+}
+
+// ReadSnapshot reads the header and file index of the snapshot at path. The node trees themselves are left on disk.
+func ReadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := &countingReader{r: bufio.NewReader(f)}
+	magic := make([]byte, len(snapshotMagic))
+	_, err = io.ReadFull(br, magic)
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != snapshotMagic {
+		return nil, errors.New("not a valid snapshot file")
+	}
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if version != snapshotVersion {
+		return nil, errors.New(fmt.Sprintf("unsupported snapshot version %d", version))
+	}
+	hash, err := readString(br)
+	if err != nil {
+		return nil, err
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{path: path, version: version, hash: hash, stamps: make(map[string]fileStamp), files: make(map[string]snapshotEntry)}
+	for i := uint64(0); i < count; i++ {
+		name, err := readString(br)
+		if err != nil {
+			return nil, err
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		modTime, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		s.stamps[name] = fileStamp{size: int64(size), modTime: int64(modTime)}
+	}
+	for i := uint64(0); i < count; i++ {
+		name, err := readString(br)
+		if err != nil {
+			return nil, err
+		}
+		length, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		s.files[name] = snapshotEntry{offset: br.n, length: int64(length)}
+		_, err = br.r.Discard(int(length))
+		if err != nil {
+			return nil, err
+		}
+		br.n += int64(length)
+	}
+	return s, nil
+}
+
+// countingReader tracks the offset reached within the underlying reader.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+func writeUvarint(w *bufio.Writer, v uint64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, v)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func writeString(w *bufio.Writer, s string) error {
+	err := writeUvarint(w, uint64(len(s)))
+	if err != nil {
+		return err
+	}
+	_, err = w.WriteString(s)
+	return err
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+func readString(r byteReader) (string, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, l)
+	_, err = io.ReadFull(r, b)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
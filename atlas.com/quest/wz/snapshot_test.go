@@ -0,0 +1,140 @@
+package wz
+
+import (
+	"atlas-quest/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const questWzDir = "../../../wz/Quest.wz"
+
+func countNodes(n xml.Parent) int {
+	c := 1
+	for _, cn := range n.Children() {
+		if p, ok := cn.(xml.Parent); ok {
+			c += countNodes(p)
+		} else {
+			c++
+		}
+	}
+	return c
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quest.snapshot")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := Compile(questWzDir, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Hash() != hash {
+		t.Errorf("Hash() = %s, want %s", s.Hash(), hash)
+	}
+
+	for _, name := range []string{"Act.img.xml", "Check.img.xml", "QuestInfo.img.xml"} {
+		if !s.Has(name) {
+			t.Fatalf("snapshot is missing %s", name)
+		}
+		got, err := s.File(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := xml.Read(filepath.Join(questWzDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if countNodes(got) != countNodes(want) {
+			t.Errorf("File(%s) has %d nodes, want %d", name, countNodes(got), countNodes(want))
+		}
+	}
+
+	if s.Has("Missing.img.xml") {
+		t.Errorf("Has(Missing.img.xml) = true, want false")
+	}
+	_, err = s.File("Missing.img.xml")
+	if err == nil {
+		t.Errorf("File(Missing.img.xml) error = nil, want not found")
+	}
+}
+
+func TestSnapshotCurrent(t *testing.T) {
+	dir := t.TempDir()
+	es, err := Read(questWzDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range es {
+		b, err := os.ReadFile(e.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, e.Name()), b, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "quest.snapshot")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Compile(dir, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string, want bool) {
+		es, err := Read(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Current(es)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: Current() = %t, want %t", name, got, want)
+		}
+	}
+	check("unchanged", true)
+
+	act := filepath.Join(dir, "Act.img.xml")
+	later := time.Now().Add(time.Hour)
+	err = os.Chtimes(act, later, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("touched", true)
+
+	b, err := os.ReadFile(act)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(act, append(b, '\n'), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("modified", false)
+}
@@ -0,0 +1,70 @@
+package wz
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watch polls the directory at path every interval, and invokes onChange whenever a file is added, removed, or
+// modified. Should onChange fail, it is invoked again on the next poll until it succeeds. Polling stops when ctx is
+// cancelled.
+func Watch(l logrus.FieldLogger, ctx context.Context, wg *sync.WaitGroup, path string, interval time.Duration, onChange func() error) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		last, err := fingerprint(path)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to read [%s] for change detection.", path)
+		}
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				current, err := fingerprint(path)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to read [%s] for change detection.", path)
+					continue
+				}
+				if current == last {
+					continue
+				}
+				l.Infof("Detected change in [%s].", path)
+				err = onChange()
+				if err != nil {
+					l.WithError(err).Warnf("Unable to apply change in [%s], retrying in %s.", path, interval)
+					continue
+				}
+				last = current
+			}
+		}
+	}()
+}
+
+func fingerprint(path string) (string, error) {
+	es, err := Read(path)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0)
+	for _, e := range es {
+		stat, err := os.Stat(e.Path())
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", e.Path(), stat.Size(), stat.ModTime().UnixNano()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|"), nil
+}
@@ -0,0 +1,60 @@
+package wz
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchRetriesFailedChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Quest.img.xml")
+	err := os.WriteFile(path, []byte("a"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	calls := make(chan int, 100)
+	count := 0
+	Watch(l, ctx, wg, dir, 10*time.Millisecond, func() error {
+		count++
+		calls <- count
+		if count < 3 {
+			return errors.New("reload failed")
+		}
+		return nil
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	err = os.WriteFile(path, []byte("ab"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for n := 0; n < 3; {
+		select {
+		case n = <-calls:
+		case <-deadline:
+			t.Fatalf("change applied %d times, want a retry until it succeeds", n)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	wg.Wait()
+	if len(calls) != 0 {
+		t.Errorf("change applied again after succeeding")
+	}
+}
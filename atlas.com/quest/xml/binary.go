@@ -0,0 +1,271 @@
+package xml
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	kindDirectory byte = iota + 1
+	kindCanvas
+	kindInteger
+	kindShort
+	kindLong
+	kindFloat
+	kindDouble
+	kindString
+	kindPoint
+	kindNull
+	kindUOL
+	kindSound
+	kindUnsupported
+)
+
+// WriteBinary encodes the tree rooted at n in a compact binary form, suitable for reading back with ReadBinary. Strings
+// are interned, so repeated node names are only written once.
+func WriteBinary(w io.Writer, n *Node) error {
+	bw := bufio.NewWriter(w)
+	e := &binaryEncoder{w: bw, strings: make(map[string]uint64)}
+	err := e.node(n)
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+type binaryEncoder struct {
+	w       *bufio.Writer
+	strings map[string]uint64
+	buf     [binary.MaxVarintLen64]byte
+}
+
+func (e *binaryEncoder) uvarint(v uint64) error {
+	n := binary.PutUvarint(e.buf[:], v)
+	_, err := e.w.Write(e.buf[:n])
+	return err
+}
+
+func (e *binaryEncoder) string(s string) error {
+	if idx, ok := e.strings[s]; ok {
+		return e.uvarint(idx + 1)
+	}
+	e.strings[s] = uint64(len(e.strings))
+	err := e.uvarint(0)
+	if err != nil {
+		return err
+	}
+	err = e.uvarint(uint64(len(s)))
+	if err != nil {
+		return err
+	}
+	_, err = e.w.WriteString(s)
+	return err
+}
+
+func (e *binaryEncoder) stringList(values ...string) error {
+	for _, v := range values {
+		err := e.string(v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *binaryEncoder) children(cs []Noder) error {
+	err := e.uvarint(uint64(len(cs)))
+	if err != nil {
+		return err
+	}
+	for _, c := range cs {
+		err = e.node(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *binaryEncoder) node(n Noder) error {
+	var kind byte
+	var values []string
+	switch v := n.(type) {
+	case *Node:
+		kind = kindDirectory
+	case *CanvasNode:
+		kind, values = kindCanvas, []string{v.width, v.height}
+	case *IntegerNode:
+		kind, values = kindInteger, []string{v.value}
+	case *ShortNode:
+		kind, values = kindShort, []string{v.value}
+	case *LongNode:
+		kind, values = kindLong, []string{v.value}
+	case *FloatNode:
+		kind, values = kindFloat, []string{v.value}
+	case *DoubleNode:
+		kind, values = kindDouble, []string{v.value}
+	case *StringNode:
+		kind, values = kindString, []string{v.value}
+	case *PointNode:
+		kind, values = kindPoint, []string{v.x, v.y}
+	case *NullNode:
+		kind = kindNull
+	case *UOLNode:
+		kind, values = kindUOL, []string{v.value}
+	case *SoundNode:
+		kind, values = kindSound, []string{v.length}
+	case *UnsupportedNode:
+		kind, values = kindUnsupported, []string{v.kind}
+	default:
+		return errors.New(fmt.Sprintf("unsupported node type %T", n))
+	}
+
+	err := e.w.WriteByte(kind)
+	if err != nil {
+		return err
+	}
+	err = e.stringList(append([]string{n.Name()}, values...)...)
+	if err != nil {
+		return err
+	}
+	if p, ok := n.(Parent); ok {
+		return e.children(p.Children())
+	}
+	return nil
+}
+
+// ReadBinary decodes a tree previously written by WriteBinary.
+func ReadBinary(r io.Reader) (*Node, error) {
+	d := &binaryDecoder{r: bufio.NewReader(r), strings: make([]string, 0)}
+	n, err := d.node(nil)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := n.(*Node)
+	if !ok {
+		return nil, errors.New("invalid binary structure")
+	}
+	return root, nil
+}
+
+type binaryDecoder struct {
+	r       *bufio.Reader
+	strings []string
+}
+
+func (d *binaryDecoder) string() (string, error) {
+	idx, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return "", err
+	}
+	if idx > 0 {
+		if idx > uint64(len(d.strings)) {
+			return "", errors.New("invalid binary structure")
+		}
+		return d.strings[idx-1], nil
+	}
+
+	l, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, l)
+	_, err = io.ReadFull(d.r, b)
+	if err != nil {
+		return "", err
+	}
+	s := string(b)
+	d.strings = append(d.strings, s)
+	return s, nil
+}
+
+func (d *binaryDecoder) children(p Parent) ([]Noder, error) {
+	count, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	results := make([]Noder, 0, count)
+	for i := uint64(0); i < count; i++ {
+		c, err := d.node(p)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, c)
+	}
+	return results, nil
+}
+
+func (d *binaryDecoder) node(p Parent) (Noder, error) {
+	kind, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	name, err := d.string()
+	if err != nil {
+		return nil, err
+	}
+
+	var values [2]string
+	var count int
+	switch kind {
+	case kindDirectory, kindNull:
+		count = 0
+	case kindCanvas, kindPoint:
+		count = 2
+	case kindInteger, kindShort, kindLong, kindFloat, kindDouble, kindString, kindUOL, kindSound, kindUnsupported:
+		count = 1
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported node kind %d", kind))
+	}
+	for i := 0; i < count; i++ {
+		values[i], err = d.string()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch kind {
+	case kindDirectory:
+		n := &Node{name: name, parent: p}
+		n.children, err = d.children(n)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case kindCanvas:
+		n := &CanvasNode{name: name, width: values[0], height: values[1], parent: p}
+		n.children, err = d.children(n)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case kindInteger:
+		return &IntegerNode{name: name, value: values[0]}, nil
+	case kindShort:
+		return &ShortNode{name: name, value: values[0]}, nil
+	case kindLong:
+		return &LongNode{name: name, value: values[0]}, nil
+	case kindFloat:
+		return &FloatNode{name: name, value: values[0]}, nil
+	case kindDouble:
+		return &DoubleNode{name: name, value: values[0]}, nil
+	case kindString:
+		return &StringNode{name: name, value: values[0]}, nil
+	case kindPoint:
+		return &PointNode{name: name, x: values[0], y: values[1]}, nil
+	case kindNull:
+		return &NullNode{name: name}, nil
+	case kindUOL:
+		return &UOLNode{name: name, value: values[0], parent: p}, nil
+	case kindUnsupported:
+		return &UnsupportedNode{name: name, kind: values[0]}, nil
+	default:
+		return &SoundNode{name: name, length: values[0]}, nil
+	}
+}
@@ -1,7 +1,6 @@
 package xml
 
 import (
-	"encoding/xml"
 	"errors"
 	"strconv"
 	"strings"
@@ -43,6 +42,7 @@ type Parent interface {
 
 type Node struct {
 	name     string
+	parent   Parent
 	children []Noder
 }
 
@@ -54,57 +54,27 @@ func (n *Node) Children() []Noder {
 	return n.children
 }
 
-func (n *Node) initFromXML(i compositeNode) {
-	n.name = i.Name
-	for _, c := range i.ChildNodes {
-		child := Node{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
-	for _, c := range i.CanvasNodes {
-		child := CanvasNode{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
-	for _, c := range i.IntegerNodes {
-		child := IntegerNode{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
-	for _, c := range i.StringNodes {
-		child := StringNode{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
-	for _, c := range i.PointNodes {
-		child := PointNode{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
+func (n *Node) owner() Parent {
+	return n.parent
 }
 
-func (n *Node) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var i compositeNode
-	err := d.DecodeElement(&i, &start)
-	if err != nil {
-		return err
-	}
-	n.initFromXML(i)
-	return nil
+func (n *Node) ChildByName(name string) (Noder, error) {
+	return childByName(n, name)
 }
 
-func (n *Node) ChildByName(name string) (Noder, error) {
+// childByName walks a slash separated path beneath p, following any UOL links encountered along the way.
+func childByName(p Parent, name string) (Noder, error) {
 	segments := strings.Split(name, "/")
 	if len(segments) == 1 {
-		for _, c := range n.Children() {
+		for _, c := range p.Children() {
 			if c.Name() == name {
-				return c, nil
+				return resolve(c, 0)
 			}
 		}
 		return nil, errors.New("child not found")
 	}
 
-	is, err := n.ChildByName(segments[0])
+	is, err := p.ChildByName(segments[0])
 	if err != nil {
 		return nil, err
 	}
@@ -115,20 +85,11 @@ func (n *Node) ChildByName(name string) (Noder, error) {
 	return intermediary.ChildByName(strings.Join(segments[1:], "/"))
 }
 
-type compositeNode struct {
-	XMLName      xml.Name        `xml:"imgdir"`
-	Name         string          `xml:"name,attr"`
-	ChildNodes   []compositeNode `xml:"imgdir"`
-	CanvasNodes  []canvasNode    `xml:"canvas"`
-	IntegerNodes []integerNode   `xml:"int"`
-	StringNodes  []stringNode    `xml:"string"`
-	PointNodes   []pointNode     `xml:"vector"`
-}
-
 type CanvasNode struct {
 	name     string
 	width    string
 	height   string
+	parent   Parent
 	children []Noder
 }
 
@@ -140,170 +101,106 @@ func (n *CanvasNode) Children() []Noder {
 	return n.children
 }
 
-func (n *CanvasNode) initFromXML(i canvasNode) {
-	n.name = i.Name
-	for _, c := range i.IntegerNodes {
-		child := IntegerNode{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
-	for _, c := range i.PointNodes {
-		child := PointNode{}
-		child.initFromXML(c)
-		n.children = append(n.children, &child)
-	}
+func (n *CanvasNode) owner() Parent {
+	return n.parent
 }
 
 func (n *CanvasNode) ChildByName(name string) (Noder, error) {
-	segments := strings.Split(name, "/")
-	if len(segments) == 1 {
-		for _, c := range n.Children() {
-			if c.Name() == name {
-				return c, nil
-			}
-		}
-		return nil, errors.New("child not found")
-	}
+	return childByName(n, name)
+}
 
-	is, err := n.ChildByName(segments[0])
+func child[N Noder](n Parent, name string) (N, error) {
+	var result N
+	c, err := n.ChildByName(name)
 	if err != nil {
-		return nil, err
+		return result, errors.New("node not found")
 	}
-	intermediary, ok := is.(Parent)
+	val, ok := c.(N)
 	if !ok {
-		return nil, errors.New("child not found")
+		return result, errors.New("node not found")
 	}
-	return intermediary.ChildByName(strings.Join(segments[1:], "/"))
+	return val, nil
 }
 
-//func (i *compositeNode) GetShort(name string, def uint16) uint16 {
-//	for _, c := range i.IntegerNodes {
-//		if c.Name == name {
-//			res, err := strconv.ParseUint(c.Value, 10, 16)
-//			if err != nil {
-//				return def
-//			}
-//			return uint16(res)
-//		}
-//	}
-//	return def
-//}
-//
-//func (i *compositeNode) GetString(name string, def string) string {
-//	for _, c := range i.StringNodes {
-//		if c.Name == name {
-//			return c.Value
-//		}
-//	}
-//	return def
-//}
-//
-
 func GetInteger(n Parent, name string) (int32, error) {
-	for _, c := range n.Children() {
-		val, ok := c.(*IntegerNode)
-		if !ok {
-			continue
-		}
-
-		if val.Name() == name {
-			res, err := strconv.ParseInt(val.Value(), 10, 32)
-			if err != nil {
-				return 0, err
-			}
-			return int32(res), nil
-		}
+	val, err := child[*IntegerNode](n, name)
+	if err != nil {
+		return 0, err
 	}
-	return 0, errors.New("node not found")
+	res, err := strconv.ParseInt(val.Value(), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(res), nil
 }
 
 func GetBoolean(n Parent, name string) (bool, error) {
-	for _, c := range n.Children() {
-		val, ok := c.(*IntegerNode)
-		if !ok {
-			continue
-		}
-
-		if val.Name() == name {
-			res, err := strconv.ParseInt(val.Value(), 10, 32)
-			if err != nil {
-				return false, err
-			}
-			return res == 1, nil
-		}
+	res, err := GetInteger(n, name)
+	if err != nil {
+		return false, err
 	}
-	return false, errors.New("node not found")
+	return res == 1, nil
 }
 
 func GetIntegerWithDefault(n Parent, name string, def int32) int32 {
-	for _, c := range n.Children() {
-		val, ok := c.(*IntegerNode)
-		if !ok {
-			continue
-		}
+	res, err := GetInteger(n, name)
+	if err != nil {
+		return def
+	}
+	return res
+}
 
-		if val.Name() == name {
-			res, err := strconv.ParseInt(val.Value(), 10, 32)
-			if err != nil {
-				return def
-			}
-			return int32(res)
-		}
+func GetShort(n Parent, name string) (int16, error) {
+	val, err := child[*ShortNode](n, name)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseInt(val.Value(), 10, 16)
+	if err != nil {
+		return 0, err
 	}
-	return def
+	return int16(res), nil
 }
 
-func GetString(n Parent, name string) (string, error) {
-	for _, c := range n.Children() {
-		val, ok := c.(*StringNode)
-		if !ok {
-			continue
-		}
+func GetLong(n Parent, name string) (int64, error) {
+	val, err := child[*LongNode](n, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(val.Value(), 10, 64)
+}
 
-		if val.Name() == name {
-			return val.Value(), nil
-		}
+func GetFloat(n Parent, name string) (float32, error) {
+	val, err := child[*FloatNode](n, name)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseFloat(val.Value(), 32)
+	if err != nil {
+		return 0, err
 	}
-	return "", errors.New("node not found")
-}
-
-//
-//func (i *compositeNode) GetFloatWithDefault(name string, def float64) float64 {
-//	for _, c := range i.IntegerNodes {
-//		if c.Name == name {
-//			res, err := strconv.ParseFloat(c.Value, 64)
-//			if err != nil {
-//				return def
-//			}
-//			return res
-//		}
-//	}
-//	return def
-//}
-//
-//func (i *compositeNode) GetPoint(name string, defX int32, defY int32) (int32, int32) {
-//	for _, c := range i.PointNodes {
-//		if c.Name == name {
-//			x, err := strconv.ParseInt(c.X, 10, 32)
-//			if err != nil {
-//				return defX, defY
-//			}
-//			y, err := strconv.ParseInt(c.Y, 10, 32)
-//			if err != nil {
-//				return defX, defY
-//			}
-//			return int32(x), int32(y)
-//		}
-//	}
-//	return defX, defY
-//}
-
-type canvasNode struct {
-	Name         string        `xml:"name,attr"`
-	Width        string        `xml:"width,attr"`
-	Height       string        `xml:"height,attr"`
-	IntegerNodes []integerNode `xml:"int"`
-	PointNodes   []pointNode   `xml:"vector"`
+	return float32(res), nil
+}
+
+func GetDouble(n Parent, name string) (float64, error) {
+	val, err := child[*DoubleNode](n, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(val.Value(), 64)
+}
+
+func GetString(n Parent, name string) (string, error) {
+	val, err := child[*StringNode](n, name)
+	if err != nil {
+		return "", err
+	}
+	return val.Value(), nil
+}
+
+func IsNull(n Parent, name string) bool {
+	_, err := child[*NullNode](n, name)
+	return err == nil
 }
 
 type IntegerNode struct {
@@ -319,88 +216,203 @@ func (n *IntegerNode) Value() string {
 	return n.value
 }
 
-func (n *IntegerNode) initFromXML(i integerNode) {
-	n.name = i.Name
-	n.value = i.Value
+type StringNode struct {
+	name  string
+	value string
 }
 
-type integerNode struct {
-	Name  string `xml:"name,attr"`
-	Value string `xml:"value,attr"`
+func (n *StringNode) Name() string {
+	return n.name
 }
 
-type StringNode struct {
+func (n *StringNode) Value() string {
+	return n.value
+}
+
+type PointNode struct {
+	name string
+	x    string
+	y    string
+}
+
+func (n *PointNode) Name() string {
+	return n.name
+}
+
+type ShortNode struct {
 	name  string
 	value string
 }
 
-func (n *StringNode) Name() string {
+func (n *ShortNode) Name() string {
 	return n.name
 }
 
-func (n *StringNode) initFromXML(i stringNode) {
-	n.name = i.Name
-	n.value = i.Value
+func (n *ShortNode) Value() string {
+	return n.value
+}
+
+type LongNode struct {
+	name  string
+	value string
 }
 
-func (n *StringNode) Value() string {
+func (n *LongNode) Name() string {
+	return n.name
+}
+
+func (n *LongNode) Value() string {
 	return n.value
 }
 
-type stringNode struct {
-	Name  string `xml:"name,attr"`
-	Value string `xml:"value,attr"`
+type FloatNode struct {
+	name  string
+	value string
 }
 
-type PointNode struct {
+func (n *FloatNode) Name() string {
+	return n.name
+}
+
+func (n *FloatNode) Value() string {
+	return n.value
+}
+
+type DoubleNode struct {
+	name  string
+	value string
+}
+
+func (n *DoubleNode) Name() string {
+	return n.name
+}
+
+func (n *DoubleNode) Value() string {
+	return n.value
+}
+
+// NullNode marks a property which is present in the data, but carries no value.
+type NullNode struct {
 	name string
-	x    string
-	y    string
 }
 
-func (n *PointNode) Name() string {
+func (n *NullNode) Name() string {
+	return n.name
+}
+
+// UOLNode is a link to another node, expressed as a path relative to the directory containing the link.
+type UOLNode struct {
+	name   string
+	value  string
+	parent Parent
+}
+
+func (n *UOLNode) Name() string {
 	return n.name
 }
 
-func (n *PointNode) initFromXML(i pointNode) {
-	n.name = i.Name
-	n.x = i.X
-	n.y = i.Y
-}
-
-type pointNode struct {
-	Name string `xml:"name,attr"`
-	X    string `xml:"x,attr"`
-	Y    string `xml:"y,attr"`
-}
-
-//
-//func (i *CanvasNode) GetIntegerWithDefault(name string, def int32) int32 {
-//	for _, c := range i.IntegerNodes {
-//		if c.Name == name {
-//			res, err := strconv.ParseUint(c.Value, 10, 32)
-//			if err != nil {
-//				return def
-//			}
-//			return int32(res)
-//		}
-//	}
-//	return def
-//}
-//
-//func (i *CanvasNode) GetPoint(name string, defX int32, defY int32) (int32, int32) {
-//	for _, c := range i.PointNodes {
-//		if c.Name == name {
-//			x, err := strconv.ParseInt(c.X, 10, 32)
-//			if err != nil {
-//				return defX, defY
-//			}
-//			y, err := strconv.ParseInt(c.Y, 10, 32)
-//			if err != nil {
-//				return defX, defY
-//			}
-//			return int32(x), int32(y)
-//		}
-//	}
-//	return defX, defY
-//}
+func (n *UOLNode) Value() string {
+	return n.value
+}
+
+// Resolve follows the link to the node it references.
+func (n *UOLNode) Resolve() (Noder, error) {
+	return resolve(n, 0)
+}
+
+type SoundNode struct {
+	name   string
+	length string
+}
+
+func (n *SoundNode) Name() string {
+	return n.name
+}
+
+func (n *SoundNode) Length() string {
+	return n.length
+}
+
+// UnsupportedNode stands in for an element of a type the reader does not understand. Its subtree is skipped, rather
+// than failing the whole file.
+type UnsupportedNode struct {
+	name string
+	kind string
+}
+
+func (n *UnsupportedNode) Name() string {
+	return n.name
+}
+
+// Kind is the element name of the skipped node.
+func (n *UnsupportedNode) Kind() string {
+	return n.kind
+}
+
+// WalkUnsupported calls f with the slash separated path, relative to p, of each unsupported node beneath it.
+func WalkUnsupported(p Parent, f func(path string, n *UnsupportedNode)) {
+	walkUnsupported(p, "", f)
+}
+
+func walkUnsupported(p Parent, prefix string, f func(path string, n *UnsupportedNode)) {
+	for _, c := range p.Children() {
+		switch v := c.(type) {
+		case *UnsupportedNode:
+			f(prefix+v.Name(), v)
+		case Parent:
+			walkUnsupported(v, prefix+v.Name()+"/", f)
+		}
+	}
+}
+
+type owned interface {
+	owner() Parent
+}
+
+const maxLinkDepth = 16
+
+func resolve(n Noder, depth int) (Noder, error) {
+	link, ok := n.(*UOLNode)
+	if !ok {
+		return n, nil
+	}
+	if depth >= maxLinkDepth {
+		return nil, errors.New("uol link depth exceeded")
+	}
+
+	var current Noder = link.parent
+	for _, segment := range strings.Split(link.Value(), "/") {
+		if segment == "" || segment == "." {
+			continue
+		}
+		if segment == ".." {
+			o, ok := current.(owned)
+			if !ok || o.owner() == nil {
+				return nil, errors.New("uol link escapes root")
+			}
+			current = o.owner()
+			continue
+		}
+
+		p, ok := current.(Parent)
+		if !ok {
+			return nil, errors.New("child not found")
+		}
+		var next Noder
+		for _, c := range p.Children() {
+			if c.Name() == segment {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil, errors.New("child not found")
+		}
+		resolved, err := resolve(next, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		current = resolved
+	}
+	return current, nil
+}
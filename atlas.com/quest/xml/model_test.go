@@ -0,0 +1,214 @@
+package xml
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<imgdir name="sample.img">
+  <imgdir name="values">
+    <short name="short" value="-1200"/>
+    <short name="shortOverflow" value="40000"/>
+    <long name="long" value="9000000000"/>
+    <float name="float" value="0.25"/>
+    <double name="double" value="1.5e10"/>
+    <int name="int" value="7"/>
+    <string name="string" value="text"/>
+    <null name="null"/>
+  </imgdir>
+  <imgdir name="links">
+    <imgdir name="target">
+      <int name="value" value="42"/>
+    </imgdir>
+    <uol name="sibling" value="target"/>
+    <uol name="chained" value="sibling"/>
+    <imgdir name="nested">
+      <uol name="parent" value="../target"/>
+      <uol name="escape" value="../../../values"/>
+    </imgdir>
+    <uol name="missing" value="nowhere"/>
+    <uol name="first" value="second"/>
+    <uol name="second" value="first"/>
+    <uol name="self" value="self"/>
+  </imgdir>
+</imgdir>`
+
+func decodeSample(t *testing.T) *Node {
+	root, err := Decode(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func sampleDir(t *testing.T, name string) Parent {
+	n, err := decodeSample(t).ChildByName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n.(Parent)
+}
+
+func TestGetShort(t *testing.T) {
+	values := sampleDir(t, "values")
+	for _, tc := range []struct {
+		name string
+		want int16
+		ok   bool
+	}{
+		{"short", -1200, true},
+		{"shortOverflow", 0, false},
+		{"int", 0, false},
+		{"absent", 0, false},
+	} {
+		got, err := GetShort(values, tc.name)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("GetShort(%s) = %d, %v, want %d, ok %v", tc.name, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestGetLong(t *testing.T) {
+	values := sampleDir(t, "values")
+	for _, tc := range []struct {
+		name string
+		want int64
+		ok   bool
+	}{
+		{"long", 9000000000, true},
+		{"short", 0, false},
+		{"absent", 0, false},
+	} {
+		got, err := GetLong(values, tc.name)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("GetLong(%s) = %d, %v, want %d, ok %v", tc.name, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestGetFloat(t *testing.T) {
+	values := sampleDir(t, "values")
+	for _, tc := range []struct {
+		name string
+		want float32
+		ok   bool
+	}{
+		{"float", 0.25, true},
+		{"double", 0, false},
+		{"absent", 0, false},
+	} {
+		got, err := GetFloat(values, tc.name)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("GetFloat(%s) = %f, %v, want %f, ok %v", tc.name, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestGetDouble(t *testing.T) {
+	values := sampleDir(t, "values")
+	for _, tc := range []struct {
+		name string
+		want float64
+		ok   bool
+	}{
+		{"double", 1.5e10, true},
+		{"float", 0, false},
+		{"absent", 0, false},
+	} {
+		got, err := GetDouble(values, tc.name)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("GetDouble(%s) = %f, %v, want %f, ok %v", tc.name, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestIsNull(t *testing.T) {
+	values := sampleDir(t, "values")
+	for _, tc := range []struct {
+		name string
+		want bool
+	}{
+		{"null", true},
+		{"int", false},
+		{"string", false},
+		{"absent", false},
+	} {
+		if got := IsNull(values, tc.name); got != tc.want {
+			t.Errorf("IsNull(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := decodeSample(t)
+	for _, tc := range []struct {
+		path string
+		want int32
+		ok   bool
+	}{
+		{"links/target/value", 42, true},
+		{"links/sibling/value", 42, true},
+		{"links/chained/value", 42, true},
+		{"links/nested/parent/value", 42, true},
+		{"links/missing/value", 0, false},
+		{"links/first/value", 0, false},
+		{"links/self/value", 0, false},
+	} {
+		got, err := GetInteger(root, tc.path)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("GetInteger(%s) = %d, %v, want %d, ok %v", tc.path, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestResolveLink(t *testing.T) {
+	links := sampleDir(t, "links")
+	for _, tc := range []struct {
+		name string
+		want string
+		err  string
+	}{
+		{name: "sibling", want: "target"},
+		{name: "chained", want: "target"},
+		{name: "missing", err: "child not found"},
+		{name: "first", err: "uol link depth exceeded"},
+		{name: "self", err: "uol link depth exceeded"},
+	} {
+		var link *UOLNode
+		for _, c := range links.Children() {
+			if c.Name() == tc.name {
+				link = c.(*UOLNode)
+			}
+		}
+		if link == nil {
+			t.Fatalf("link %s not found", tc.name)
+		}
+
+		n, err := link.Resolve()
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Resolve(%s) error = %v, want %s", tc.name, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%s) error = %v", tc.name, err)
+			continue
+		}
+		if n.Name() != tc.want {
+			t.Errorf("Resolve(%s) = %s, want %s", tc.name, n.Name(), tc.want)
+		}
+	}
+}
+
+func TestResolveEscapesRoot(t *testing.T) {
+	n, err := sampleDir(t, "links").ChildByName("nested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = n.(Parent).ChildByName("escape")
+	if err == nil || err.Error() != "uol link escapes root" {
+		t.Errorf("ChildByName(escape) error = %v, want uol link escapes root", err)
+	}
+}
@@ -1,13 +1,62 @@
 package xml
 
 import (
+	"bufio"
 	"encoding/xml"
 	"errors"
-	"io/ioutil"
+	"io"
 	"os"
+	"strings"
 )
 
-func Read(path string) (*Node, error) {
+type readConfiguration struct {
+	selected selection
+}
+
+type ReadConfigurator func(c *readConfiguration)
+
+// Only restricts the read to the subtrees identified by the given slash separated paths, relative to the root. Anything
+// outside those subtrees is skipped by the decoder and never materialized.
+func Only(paths ...string) ReadConfigurator {
+	return func(c *readConfiguration) {
+		if c.selected == nil {
+			c.selected = make(selection)
+		}
+		for _, p := range paths {
+			c.selected.add(strings.Split(p, "/"))
+		}
+	}
+}
+
+// selection is a tree of node names to retain while reading. A name mapped to nil retains the entire subtree, while a
+// nil selection retains everything.
+type selection map[string]selection
+
+func (s selection) add(segments []string) {
+	sub, ok := s[segments[0]]
+	if ok && sub == nil {
+		return
+	}
+	if len(segments) == 1 {
+		s[segments[0]] = nil
+		return
+	}
+	if sub == nil {
+		sub = make(selection)
+		s[segments[0]] = sub
+	}
+	sub.add(segments[1:])
+}
+
+func (s selection) child(name string) (selection, bool) {
+	if s == nil {
+		return nil, true
+	}
+	sub, ok := s[name]
+	return sub, ok
+}
+
+func Read(path string, configurators ...ReadConfigurator) (*Node, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -23,16 +72,128 @@ func Read(path string) (*Node, error) {
 		return nil, errors.New("not a valid xml file")
 	}
 
-	byteValue, err := ioutil.ReadAll(f)
-	if err != nil {
-		return nil, err
+	return Decode(bufio.NewReader(f), configurators...)
+}
+
+// Decode builds a Node tree directly from the token stream, without an intermediate representation.
+func Decode(r io.Reader, configurators ...ReadConfigurator) (*Node, error) {
+	c := &readConfiguration{}
+	for _, configurator := range configurators {
+		configurator(c)
+	}
+
+	d := xml.NewDecoder(r)
+	for {
+		t, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("not a valid xml file")
+			}
+			return nil, err
+		}
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "imgdir" {
+			return nil, errors.New("not a valid xml file")
+		}
+
+		root := &Node{name: attribute(start, "name")}
+		root.children, err = readChildren(d, root, c.selected)
+		if err != nil {
+			return nil, err
+		}
+		return root, nil
 	}
+}
 
-	var equipment Node
-	err = xml.Unmarshal(byteValue, &equipment)
+func readChildren(d *xml.Decoder, p Parent, s selection) ([]Noder, error) {
+	var children []Noder
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch e := t.(type) {
+		case xml.StartElement:
+			sub, ok := s.child(attribute(e, "name"))
+			if !ok {
+				err = d.Skip()
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			c, err := readNode(d, e, p, sub)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, c)
+		case xml.EndElement:
+			return children, nil
+		}
+	}
+}
+
+func readNode(d *xml.Decoder, e xml.StartElement, p Parent, s selection) (Noder, error) {
+	name := attribute(e, "name")
+	switch e.Name.Local {
+	case "imgdir":
+		n := &Node{name: name, parent: p}
+		children, err := readChildren(d, n, s)
+		if err != nil {
+			return nil, err
+		}
+		n.children = children
+		return n, nil
+	case "canvas":
+		n := &CanvasNode{name: name, width: attribute(e, "width"), height: attribute(e, "height"), parent: p}
+		children, err := readChildren(d, n, s)
+		if err != nil {
+			return nil, err
+		}
+		n.children = children
+		return n, nil
+	}
+
+	var n Noder
+	switch e.Name.Local {
+	case "int":
+		n = &IntegerNode{name: name, value: attribute(e, "value")}
+	case "short":
+		n = &ShortNode{name: name, value: attribute(e, "value")}
+	case "long":
+		n = &LongNode{name: name, value: attribute(e, "value")}
+	case "float":
+		n = &FloatNode{name: name, value: attribute(e, "value")}
+	case "double":
+		n = &DoubleNode{name: name, value: attribute(e, "value")}
+	case "string":
+		n = &StringNode{name: name, value: attribute(e, "value")}
+	case "vector":
+		n = &PointNode{name: name, x: attribute(e, "x"), y: attribute(e, "y")}
+	case "null":
+		n = &NullNode{name: name}
+	case "uol":
+		n = &UOLNode{name: name, value: attribute(e, "value"), parent: p}
+	case "sound":
+		n = &SoundNode{name: name, length: attribute(e, "length")}
+	default:
+		n = &UnsupportedNode{name: name, kind: e.Name.Local}
+	}
+	err := d.Skip()
 	if err != nil {
 		return nil, err
 	}
+	return n, nil
+}
 
-	return &equipment, nil
+func attribute(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
 }
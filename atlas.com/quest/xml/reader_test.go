@@ -0,0 +1,323 @@
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const questWzDir = "../../../wz/Quest.wz"
+
+var questFiles = []string{"Act.img.xml", "Check.img.xml", "Exclusive.img.xml", "PQuest.img.xml", "QuestInfo.img.xml"}
+
+// legacyNode is the document shape the reader unmarshalled into before it was made to stream.
+type legacyNode struct {
+	Name         string        `xml:"name,attr"`
+	ChildNodes   []legacyNode  `xml:"imgdir"`
+	IntegerNodes []legacyValue `xml:"int"`
+	ShortNodes   []legacyValue `xml:"short"`
+	LongNodes    []legacyValue `xml:"long"`
+	FloatNodes   []legacyValue `xml:"float"`
+	DoubleNodes  []legacyValue `xml:"double"`
+	StringNodes  []legacyValue `xml:"string"`
+	PointNodes   []legacyPoint `xml:"vector"`
+	NullNodes    []legacyValue `xml:"null"`
+	UOLNodes     []legacyValue `xml:"uol"`
+	SoundNodes   []legacySound `xml:"sound"`
+}
+
+type legacyValue struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type legacyPoint struct {
+	Name string `xml:"name,attr"`
+	X    string `xml:"x,attr"`
+	Y    string `xml:"y,attr"`
+}
+
+type legacySound struct {
+	Name   string `xml:"name,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// legacyRead builds the node tree the way Read did before it was made to stream, by unmarshalling the whole document.
+func legacyRead(t *testing.T, path string) *Node {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var i legacyNode
+	err = xml.Unmarshal(b, &i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return legacyTree(i, nil)
+}
+
+func legacyTree(i legacyNode, p Parent) *Node {
+	n := &Node{name: i.Name, parent: p}
+	for _, c := range i.ChildNodes {
+		n.children = append(n.children, legacyTree(c, n))
+	}
+	for _, c := range i.IntegerNodes {
+		n.children = append(n.children, &IntegerNode{name: c.Name, value: c.Value})
+	}
+	for _, c := range i.ShortNodes {
+		n.children = append(n.children, &ShortNode{name: c.Name, value: c.Value})
+	}
+	for _, c := range i.LongNodes {
+		n.children = append(n.children, &LongNode{name: c.Name, value: c.Value})
+	}
+	for _, c := range i.FloatNodes {
+		n.children = append(n.children, &FloatNode{name: c.Name, value: c.Value})
+	}
+	for _, c := range i.DoubleNodes {
+		n.children = append(n.children, &DoubleNode{name: c.Name, value: c.Value})
+	}
+	for _, c := range i.StringNodes {
+		n.children = append(n.children, &StringNode{name: c.Name, value: c.Value})
+	}
+	for _, c := range i.PointNodes {
+		n.children = append(n.children, &PointNode{name: c.Name, x: c.X, y: c.Y})
+	}
+	for _, c := range i.NullNodes {
+		n.children = append(n.children, &NullNode{name: c.Name})
+	}
+	for _, c := range i.UOLNodes {
+		n.children = append(n.children, &UOLNode{name: c.Name, value: c.Value, parent: n})
+	}
+	for _, c := range i.SoundNodes {
+		n.children = append(n.children, &SoundNode{name: c.Name, length: c.Length})
+	}
+	return n
+}
+
+// describe renders the node and everything beneath it. The unmarshalled tree groups children by type rather than
+// keeping document order, so children are listed in sorted order.
+func describe(n Noder) string {
+	var self string
+	switch v := n.(type) {
+	case *Node:
+		self = "imgdir"
+	case *CanvasNode:
+		self = fmt.Sprintf("canvas %s %s", v.width, v.height)
+	case *IntegerNode:
+		self = "int " + v.value
+	case *ShortNode:
+		self = "short " + v.value
+	case *LongNode:
+		self = "long " + v.value
+	case *FloatNode:
+		self = "float " + v.value
+	case *DoubleNode:
+		self = "double " + v.value
+	case *StringNode:
+		self = "string " + v.value
+	case *PointNode:
+		self = fmt.Sprintf("vector %s %s", v.x, v.y)
+	case *NullNode:
+		self = "null"
+	case *UOLNode:
+		self = "uol " + v.value
+	case *SoundNode:
+		self = "sound " + v.length
+	}
+
+	p, ok := n.(Parent)
+	if !ok {
+		return fmt.Sprintf("%s[%s]", n.Name(), self)
+	}
+	children := make([]string, 0, len(p.Children()))
+	for _, c := range p.Children() {
+		children = append(children, describe(c))
+	}
+	sort.Strings(children)
+	return fmt.Sprintf("%s[%s]{%s}", n.Name(), self, strings.Join(children, ","))
+}
+
+func questFile(t *testing.T, name string) string {
+	path := filepath.Join(questWzDir, name)
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s is not available.", path)
+	}
+	return path
+}
+
+func TestReadMatchesLegacy(t *testing.T) {
+	for _, name := range questFiles {
+		t.Run(name, func(t *testing.T) {
+			path := questFile(t, name)
+			want := legacyRead(t, path)
+			got, err := Read(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Children()) != len(want.Children()) {
+				t.Fatalf("read %d top level nodes, want %d", len(got.Children()), len(want.Children()))
+			}
+			if describe(got) != describe(want) {
+				t.Errorf("read tree differs from the unmarshalled tree")
+			}
+		})
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	path := questFile(t, "Check.img.xml")
+	full, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subtree := func(n Parent, path string) string {
+		c, err := n.ChildByName(path)
+		if err != nil {
+			t.Fatalf("%s not found: %v", path, err)
+		}
+		return describe(c)
+	}
+
+	for _, tc := range []struct {
+		paths []string
+		want  map[string][]string
+	}{
+		{paths: []string{"1000"}, want: map[string][]string{"1000": nil}},
+		{paths: []string{"1000", "2000"}, want: map[string][]string{"1000": nil, "2000": nil}},
+		{paths: []string{"1000/0"}, want: map[string][]string{"1000": {"0"}}},
+		{paths: []string{"1000/0", "1000"}, want: map[string][]string{"1000": nil}},
+		{paths: []string{"1000/0/item", "1000/1"}, want: map[string][]string{"1000": {"0/item", "1"}}},
+		{paths: []string{"absent"}, want: map[string][]string{}},
+	} {
+		got, err := Read(path, Only(tc.paths...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Children()) != len(tc.want) {
+			t.Errorf("Only(%v) read %d top level nodes, want %d", tc.paths, len(got.Children()), len(tc.want))
+			continue
+		}
+		for top, within := range tc.want {
+			if within == nil {
+				if subtree(got, top) != subtree(full, top) {
+					t.Errorf("Only(%v) subtree %s differs from the full read", tc.paths, top)
+				}
+				continue
+			}
+			for _, w := range within {
+				p := top + "/" + w
+				if subtree(got, p) != subtree(full, p) {
+					t.Errorf("Only(%v) subtree %s differs from the full read", tc.paths, p)
+				}
+			}
+			n, _ := got.ChildByName(top)
+			if count := countNodes(n) - 1; count != selectedCount(t, full, top, within) {
+				t.Errorf("Only(%v) retained %d nodes beneath %s, want %d", tc.paths, count, top, selectedCount(t, full, top, within))
+			}
+		}
+	}
+}
+
+// selectedCount is the number of nodes a selection of the given paths beneath top retains, counting the intermediate
+// directories leading to each.
+func selectedCount(t *testing.T, full Parent, top string, within []string) int {
+	retained := make(map[string]bool)
+	count := 0
+	for _, w := range within {
+		segments := strings.Split(w, "/")
+		for i := 1; i < len(segments); i++ {
+			prefix := strings.Join(segments[:i], "/")
+			if !retained[prefix] {
+				retained[prefix] = true
+				count++
+			}
+		}
+		c, err := full.ChildByName(top + "/" + w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count += countNodes(c)
+	}
+	return count
+}
+
+// countNodes is the number of nodes in the tree rooted at n, including n.
+func countNodes(n Noder) int {
+	count := 1
+	if p, ok := n.(Parent); ok {
+		for _, c := range p.Children() {
+			count += countNodes(c)
+		}
+	}
+	return count
+}
+
+func TestReadSkipsUnsupported(t *testing.T) {
+	doc := `<imgdir name="root">
+    <imgdir name="0">
+        <blob name="x">
+            <int name="inner" value="1"/>
+        </blob>
+        <int name="y" value="2"/>
+    </imgdir>
+</imgdir>`
+	n, err := Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := &bytes.Buffer{}
+	err = WriteBinary(encoded, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadBinary(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, root := range map[string]*Node{"xml": n, "binary": decoded} {
+		if v, err := GetInteger(root, "0/y"); err != nil || v != 2 {
+			t.Errorf("%s: 0/y = %d, %v, want 2", name, v, err)
+		}
+		var skipped []string
+		WalkUnsupported(root, func(path string, u *UnsupportedNode) {
+			skipped = append(skipped, path+" "+u.Kind())
+		})
+		if len(skipped) != 1 || skipped[0] != "0/x blob" {
+			t.Errorf("%s: unsupported nodes = %v, want [0/x blob]", name, skipped)
+		}
+	}
+}
+
+func benchmarkFile(b *testing.B, name string, configurators ...ReadConfigurator) {
+	path := filepath.Join(questWzDir, name)
+	if _, err := os.Stat(path); err != nil {
+		b.Skipf("%s is not available.", path)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Read(path, configurators...)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRead(b *testing.B) {
+	for _, name := range questFiles {
+		b.Run(name, func(b *testing.B) {
+			benchmarkFile(b, name)
+		})
+	}
+}
+
+func BenchmarkReadSingleQuest(b *testing.B) {
+	benchmarkFile(b, "Check.img.xml", Only("1000"))
+}